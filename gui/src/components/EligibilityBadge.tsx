@@ -1,7 +1,7 @@
 import { Badge } from '@/components/ui/badge';
 import { Tooltip, TooltipContent, TooltipTrigger } from '@/components/ui/tooltip';
 import type { EligibilityStatus, ConfidenceLevel } from '@/types/api';
-import { CheckCircle2, XCircle, HelpCircle, AlertCircle } from 'lucide-react';
+import { CheckCircle2, XCircle, HelpCircle, AlertCircle, MinusCircle } from 'lucide-react';
 
 interface EligibilityBadgeProps {
   status: EligibilityStatus;
@@ -40,6 +40,12 @@ const statusConfig: Record<EligibilityStatus, {
     className: 'bg-blue-500/10 text-blue-700 border-blue-500/20 hover:bg-blue-500/20',
     icon: AlertCircle,
   },
+  not_offered: { 
+    label: 'Not on platform', 
+    variant: 'outline',
+    className: 'bg-slate-500/10 text-slate-700 border-slate-500/20 hover:bg-slate-500/20',
+    icon: MinusCircle,
+  },
 };
 
 const confidenceLabels: Record<ConfidenceLevel, string> = {
@@ -1,8 +1,8 @@
 // UPSTONK API Types
 
-export type AccountType = 'TFSA' | 'ISA' | 'IRA' | 'standard' | 'retirement_annuity';
+export type AccountType = 'TFSA' | 'ISA' | 'IRA' | 'standard' | 'retirement_annuity' | 'preservation_fund';
 export type RiskTolerance = 'conservative' | 'moderate' | 'aggressive';
-export type EligibilityStatus = 'eligible' | 'ineligible' | 'unknown' | 'conditional';
+export type EligibilityStatus = 'eligible' | 'ineligible' | 'unknown' | 'conditional' | 'not_offered';
 export type ConfidenceLevel = 'high' | 'medium' | 'low' | 'unknown';
 
 // API Request Types (matching backend DTO structure)
@@ -10,6 +10,8 @@ export interface InvestorProfileRequest {
   country: string;
   accountType: AccountType;
   currency: string;
+  platform?: string;
+  investmentAmount?: number;
 }
 
 export interface ExposureRequest {
@@ -36,6 +38,7 @@ export interface DiscoveryRequest {
   investmentVehicles: string[];
   constraints: ConstraintsRequest;
   outputOptions: OutputOptionsRequest;
+  asOf?: string; // YYYY-MM-DD; evaluate eligibility under the rules in effect on this date
 }
 
 // UI Form State Types (richer than API request)
@@ -92,8 +95,28 @@ export interface EligibilityInfo {
   confidence: ConfidenceLevel;
   justification: string;
   ruleVersion?: string;
+  asOf?: string;
   rulesPassed?: string[];
   rulesFailed?: string[];
+  ruleResults?: RuleResult[];
+  evidenceCompleteness?: number;
+  evidence?: EligibilityEvidence[];
+}
+
+export interface EligibilityEvidence {
+  rule?: string;
+  criterion: string;
+  expected: string;
+  actual: string;
+  result: 'pass' | 'fail' | 'unknown';
+  source?: DataSource;
+}
+
+export interface RuleResult {
+  rule: string;
+  version: string;
+  status: EligibilityStatus;
+  confidence: ConfidenceLevel;
 }
 
 export interface DataSource {
@@ -114,6 +137,7 @@ export interface AssetBreakdown {
   bonds: number;
   cash: number;
   commodities: number;
+  realEstate: number;
   other: number;
 }
 
@@ -138,16 +162,64 @@ export interface ETFResult {
   eligibility: EligibilityInfo;
   matchScore: number;
   rankingScore: number;
+  taxDrag?: TaxDrag;
+  costs?: CostBreakdown;
   assetBreakdown: AssetBreakdown;
   geographicBreakdown: GeographicBreakdown;
   topHoldings: Holding[];
   dataSources: DataSource[];
+  fieldProvenance?: Record<string, DataSource>;
+  dataConflicts?: string[];
+}
+
+export interface TaxDrag {
+  fundDomicile: string;
+  dividendYield: number;
+  yieldAssumed: boolean;
+  fundLevelRate: number;
+  investorLevelRate: number;
+  effectiveRate: number;
+  annualDrag: number;
+  confidence: ConfidenceLevel;
+  notes?: string[];
+  tableVersion: string;
+}
+
+// All-in holding cost, % of the amount invested per year
+export interface CostBreakdown {
+  amount: number;
+  currency: string;
+  holdingYears: number;
+  ter: number;
+  terAssumed: boolean;
+  spread: number;
+  spreadAssumed: boolean;
+  fx: number;
+  brokerage: number;
+  platformFee: number;
+  effectiveCost: number;
+  annualCost: number;
+  totalCost: number;
+  schedule: string;
+  scheduleVersion: string;
+  notes?: string[];
 }
 
 export interface APIWarning {
   code: string;
   message: string;
   severity: 'info' | 'warning' | 'error';
+  ticker?: string;
+  alternatives?: AlternativeETF[];
+}
+
+export interface AlternativeETF {
+  ticker: string;
+  name: string;
+  isin?: string;
+  exchange: string;
+  domicile: string;
+  ter: number;
 }
 
 export interface DiscoverySummary {
@@ -155,8 +227,24 @@ export interface DiscoverySummary {
   totalEligible: number;
   totalIneligible: number;
   totalUnknown: number;
+  totalNotOffered?: number;
   searchDurationMs: number;
   dataSourcesQueried: string[];
+  dataSources?: DataSourceStatus[];
+}
+
+export interface DataSourceStatus {
+  name: string;
+  status: 'answered' | 'failed' | 'skipped';
+  results: number;
+  durationMs: number;
+  error?: string;
+}
+
+// Component weights the server ranked with
+export interface RankingWeights {
+  method: 'default' | 'rank_order_centroid' | 'custom';
+  weights: Record<string, number>;
 }
 
 export interface DiscoveryResponse {
@@ -164,8 +252,133 @@ export interface DiscoveryResponse {
   results: ETFResult[];
   summary: DiscoverySummary;
   warnings: APIWarning[];
+  rankingWeights: RankingWeights;
   generatedAt: string;
   cacheHit: boolean;
+  stale: boolean;
+  dataAsOf?: string;
+}
+
+// Single-instrument eligibility explanation
+export interface InstrumentEligibility {
+  identifier: string;
+  found: boolean;
+  ticker?: string;
+  isin?: string;
+  name?: string;
+  exchange?: string;
+  eligibility?: {
+    isEligible: boolean;
+    status: EligibilityStatus;
+    confidence: ConfidenceLevel;
+    reasons: string[];
+    rulesPassed: string[];
+    rulesFailed: string[];
+    rulesSkipped?: string[];
+    evidence: (Omit<EligibilityEvidence, 'source'> & { dataSource?: DataSource })[];
+    ruleVersion: string;
+    evaluatedAt: string;
+    asOf: string;
+    ruleResults?: RuleResult[];
+    evidenceCompleteness: number;
+  };
+}
+
+export interface EligibilityResponse extends InstrumentEligibility {
+  requestId: string;
+  country: string;
+  accountType: string;
+  generatedAt: string;
+}
+
+export interface EligibilityBatchResponse {
+  requestId: string;
+  country: string;
+  accountType: string;
+  results: InstrumentEligibility[];
+  generatedAt: string;
+}
+
+// Regulation 28 portfolio check
+export interface PortfolioHolding {
+  ticker: string;
+  weight: number;
+  assetBreakdown?: AssetBreakdown;
+  geographicBreakdown?: GeographicBreakdown & { countries?: Record<string, number> };
+  topHoldings?: { name: string; ticker?: string; weight: number }[];
+}
+
+export interface Reg28LimitUtilisation {
+  category: 'offshore' | 'equity' | 'property' | 'commodities' | 'single_issuer';
+  limit: number;
+  exposure: number;
+  utilisation: number;
+  headroom: number;
+  unknownWeight: number;
+  status: 'within' | 'breach' | 'unknown';
+  detail?: string;
+  contributors?: { ticker: string; exposure: number }[];
+}
+
+export interface Reg28PortfolioResponse {
+  requestId: string;
+  status: 'compliant' | 'breach' | 'unknown';
+  compliant: boolean;
+  ruleVersion: string;
+  totalWeight: number;
+  limits: Reg28LimitUtilisation[];
+  warnings?: string[];
+  generatedAt: string;
+}
+
+// TFSA contribution planner
+export interface TFSAPlanRequest {
+  investorProfile: InvestorProfileRequest & { timeHorizonYears: number };
+  priorContributions?: { taxYear: number; amount: number }[];
+  currentValue?: number;
+  monthlyContribution: number;
+  expectedReturn?: number;
+  holdings: { ticker: string; weight: number }[];
+  startDate?: string;
+}
+
+export interface TFSAPlanResponse {
+  requestId: string;
+  ruleVersion?: string;
+  capsVersion: string;
+  annualCap: number;
+  lifetimeCap: number;
+  penaltyRate: number;
+  currentTaxYear: number;
+  contributedToDate: number;
+  annualHeadroom: number;
+  lifetimeHeadroom: number;
+  overContributions?: { taxYear: number; cap: 'annual' | 'lifetime'; amount: number; penalty: number }[];
+  schedule: {
+    taxYear: number;
+    capsVersion: string;
+    months: number;
+    contribution: number;
+    annualHeadroom: number;
+    capped: boolean;
+    cumulativeContributions: number;
+    projectedValue: number;
+  }[];
+  lifetimeCapYear?: number;
+  projection: {
+    years: number;
+    expectedReturn: number;
+    weightedTer: number;
+    netReturn: number;
+    startingValue: number;
+    totalContributions: number;
+    projectedValue: number;
+    taxFreeGrowth: number;
+  };
+  allocations: { ticker: string; weight: number; firstMonthAmount: number; totalContributions: number }[];
+  warnings?: string[];
+  holdings: { ticker: string; weight: number; found: boolean; name?: string; status?: EligibilityStatus; isEligible: boolean; ter?: number }[];
+  generatedAt: string;
 }
 
 export interface APIError {
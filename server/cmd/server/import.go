@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"upstonk/internal/config"
+	"upstonk/internal/service/catalog"
+)
+
+// runImport implements the "import" subcommand:
+//
+//	server import [-source name] [-reliability Secondary] [-dry-run] file.csv [file.json ...]
+//
+// It prints one JSON report per file and returns a non-zero exit code if any
+// file failed to parse or any row was rejected.
+func runImport(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	source := flags.String("source", "", "source name recorded on each record (defaults to the file name)")
+	reliability := flags.String("reliability", "Secondary", "data reliability: Primary, Secondary or Tertiary")
+	dryRun := flags.Bool("dry-run", false, "validate without writing to the catalog")
+	catalogPath := flags.String("catalog", cfg.Catalog.Path, "catalog file to import into")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: server import [flags] file.csv|file.json ...")
+		flags.PrintDefaults()
+		return 2
+	}
+
+	store, err := catalog.Open(*catalogPath)
+	if err != nil {
+		log.Printf("Failed to open catalog: %v", err)
+		return 1
+	}
+	store.SetMergePolicy(mergePolicy(cfg))
+
+	importer := catalog.NewImporter(store)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		report, err := importer.ImportFile(path, catalog.ImportOptions{
+			SourceName:  *source,
+			Reliability: *reliability,
+			DryRun:      *dryRun,
+		})
+		if err != nil {
+			log.Printf("Import of %s failed: %v", path, err)
+			exitCode = 1
+			continue
+		}
+
+		encoder.Encode(report)
+		if report.Rejected > 0 {
+			exitCode = 1
+		}
+	}
+
+	log.Printf("Catalog %s now holds %d records", *catalogPath, store.Len())
+	return exitCode
+}
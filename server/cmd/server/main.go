@@ -14,11 +14,15 @@ import (
 	"upstonk/internal/api/handlers"
 	"upstonk/internal/api/middleware"
 	"upstonk/internal/config"
+	"upstonk/internal/service/catalog"
+	"upstonk/internal/service/cost"
 	"upstonk/internal/service/discovery"
 	"upstonk/internal/service/eligibility"
 	"upstonk/internal/service/eligibility/rules"
 	"upstonk/internal/service/ranking"
+	"upstonk/internal/service/reg28"
 	"upstonk/internal/service/search"
+	"upstonk/internal/service/tfsa"
 )
 
 func main() {
@@ -28,9 +32,21 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(cfg, os.Args[2:]))
+	}
+
+	// Background workers stop when the server shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Initialize services
-	searchProvider := initializeSearchProvider(cfg)
-	eligibilityEngine := initializeEligibilityEngine()
+	catalogStore := initializeCatalog(cfg)
+	universe := initializeUniverse(ctx, cfg)
+	liveProvider := initializeLiveProviders(cfg, universe)
+	searchProvider := initializeSearchProvider(ctx, cfg, catalogStore, liveProvider)
+	eligibilityEngine, ruleSets := initializeEligibilityEngine(cfg)
 	rankingEngine := initializeRankingEngine()
 
 	discoveryService := discovery.NewService(
@@ -38,12 +54,57 @@ func main() {
 		eligibilityEngine,
 		rankingEngine,
 	)
+	if catalogStore != nil {
+		discoveryService.SetCatalog(catalogStore)
+	}
+	costModel := initializeCostModel(cfg)
+	discoveryService.SetCostModel(costModel)
 
 	// Initialize handlers
 	discoveryHandler := handlers.NewDiscoveryHandler(discoveryService)
+	discoveryHandler.RegisterHealthCheck("cache", func() interface{} {
+		return liveProvider.CacheStats()
+	})
+	discoveryHandler.RegisterHealthCheck("providers", func() interface{} {
+		return liveProvider.ProviderHealth()
+	})
+	discoveryHandler.RegisterHealthCheck("universe", func() interface{} {
+		return universe.Info()
+	})
+	discoveryHandler.RegisterHealthCheck("rules", func() interface{} {
+		return ruleSets
+	})
+
+	admin := handlers.AdminAuth{Key: cfg.AdminAPIKey, Open: cfg.AdminOpen}
+	switch {
+	case admin.Key == "" && admin.Open:
+		log.Printf("WARNING: ADMIN_OPEN is set - admin endpoints accept unauthenticated requests")
+	case admin.Key == "":
+		log.Printf("ADMIN_API_KEY is not set - admin endpoints are disabled")
+	}
+
+	var catalogHandler *handlers.CatalogHandler
+	if catalogStore != nil {
+		catalogHandler = handlers.NewCatalogHandler(catalog.NewImporter(catalogStore), admin)
+	}
+
+	universeHandler := handlers.NewUniverseHandler(universe, admin)
+	complianceHandler := handlers.NewComplianceHandler(catalogStore, reg28.DefaultLimits())
+
+	planner := initializeTFSAPlanner(cfg)
+	plannerHandler := handlers.NewPlannerHandler(discoveryService, planner)
+	discoveryHandler.RegisterHealthCheck("tfsaCaps", func() interface{} {
+		return planner.Caps().Versions()
+	})
+	discoveryHandler.RegisterHealthCheck("brokerageSchedules", func() interface{} {
+		return map[string]interface{}{
+			"version":   costModel.Schedules().Version(),
+			"schedules": costModel.Schedules().List(),
+		}
+	})
 
 	// Setup router
-	router := setupRouter(discoveryHandler)
+	router := setupRouter(discoveryHandler, catalogHandler, universeHandler, complianceHandler, plannerHandler)
 
 	// Create server
 	server := &http.Server{
@@ -66,7 +127,7 @@ func main() {
 	gracefulShutdown(server)
 }
 
-func setupRouter(discoveryHandler *handlers.DiscoveryHandler) *mux.Router {
+func setupRouter(discoveryHandler *handlers.DiscoveryHandler, catalogHandler *handlers.CatalogHandler, universeHandler *handlers.UniverseHandler, complianceHandler *handlers.ComplianceHandler, plannerHandler *handlers.PlannerHandler) *mux.Router {
 	router := mux.NewRouter()
 
 	// Global middleware
@@ -84,42 +145,222 @@ func setupRouter(discoveryHandler *handlers.DiscoveryHandler) *mux.Router {
 	// Top performers endpoint
 	v1.HandleFunc("/discover/{type}", discoveryHandler.HandleTopPerformers).Methods("GET")
 
+	// Single-instrument eligibility explanation
+	v1.HandleFunc("/eligibility/batch", discoveryHandler.HandleEligibilityBatch).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/eligibility/{identifier}", discoveryHandler.HandleEligibility).Methods("GET")
+
+	// Portfolio compliance
+	v1.HandleFunc("/compliance/reg28/portfolio", complianceHandler.HandleReg28Portfolio).Methods("POST", "OPTIONS")
+
+	// Contribution planning
+	v1.HandleFunc("/planner/tfsa", plannerHandler.HandleTFSAPlan).Methods("POST", "OPTIONS")
+
 	// Health check
 	v1.HandleFunc("/health", discoveryHandler.HandleHealth).Methods("GET")
 
+	// Admin endpoints
+	v1.HandleFunc("/admin/universe/reload", universeHandler.HandleReload).Methods("POST")
+	if catalogHandler != nil {
+		v1.HandleFunc("/admin/catalog/import", catalogHandler.HandleImport).Methods("POST")
+	}
+
 	// Documentation endpoint
 	router.HandleFunc("/", serveDocumentation).Methods("GET")
 
 	return router
 }
 
-func initializeSearchProvider(cfg *config.Config) search.Provider {
+func initializeCatalog(cfg *config.Config) *catalog.Store {
+	if !cfg.Catalog.Enabled {
+		return nil
+	}
+
+	store, err := catalog.Open(cfg.Catalog.Path)
+	if err != nil {
+		log.Printf("Failed to open ETF catalog, querying live providers directly: %v", err)
+		return nil
+	}
+
+	store.SetMergePolicy(mergePolicy(cfg))
+
+	log.Printf("ETF catalog loaded from %s (%d records)", cfg.Catalog.Path, store.Len())
+	return store
+}
+
+// mergePolicy is the configured source precedence and conflict tolerance,
+// shared by the live search, the catalog and the import command
+func mergePolicy(cfg *config.Config) search.MergePolicy {
+	return search.MergePolicy{
+		Precedence:       cfg.Merge.SourcePrecedence,
+		NumericTolerance: cfg.Merge.ConflictTolerance,
+	}
+}
+
+func initializeSearchProvider(ctx context.Context, cfg *config.Config, store *catalog.Store, liveProvider *search.AggregatedProvider) search.Provider {
+	if store == nil {
+		return liveProvider
+	}
+
+	// Live providers refresh the catalog in the background instead of being queried per request
+	refresher := catalog.NewRefresher(store, liveProvider,
+		time.Duration(cfg.Catalog.RefreshIntervalSeconds)*time.Second)
+	refresher.Start(ctx)
+
+	return catalog.NewProvider(store, liveProvider)
+}
+
+// initializeUniverse loads the ticker registry and watches it for changes.
+// A missing or invalid file leaves the live providers with no tickers.
+func initializeUniverse(ctx context.Context, cfg *config.Config) *search.Universe {
+	universe, err := search.LoadUniverse(cfg.Universe.Path)
+	if err != nil {
+		log.Printf("Failed to load ticker universe, live ticker lookups disabled: %v", err)
+		return search.NewUniverse("empty")
+	}
+
+	universe.Watch(ctx, time.Duration(cfg.Universe.ReloadIntervalSeconds)*time.Second)
+	info := universe.Info()
+	log.Printf("Ticker universe %s loaded from %s (%d tickers)", info.Version, info.Path, info.Tickers)
+	return universe
+}
+
+func initializeLiveProviders(cfg *config.Config, universe *search.Universe) *search.AggregatedProvider {
+	// Outbound traffic can be recorded to or replayed from fixtures for offline runs
+	fixtureMode := search.FixtureMode(cfg.HTTPFixtures.Mode)
+	httpClient := search.NewHTTPClient(fixtureMode, cfg.HTTPFixtures.Dir)
+	if fixtureMode != search.FixtureModeLive {
+		log.Printf("HTTP fixtures: %s mode using %s", fixtureMode, cfg.HTTPFixtures.Dir)
+	}
+
+	// Each provider gets its own timeout, health tracking and circuit breaker
+	breaker := search.BreakerConfig{
+		Timeout:          time.Duration(cfg.Providers.TimeoutSeconds) * time.Second,
+		FailureThreshold: cfg.Providers.FailureThreshold,
+		Cooldown:         time.Duration(cfg.Providers.CooldownSeconds) * time.Second,
+	}
+
+	// Yahoo Finance, ETF.com, JSE with bounded concurrent fetching
+	live := search.NewLiveProvider(httpClient, universe)
+	live.SetFetchConfig(search.FetchConfig{
+		Workers:    cfg.Providers.FetchWorkers,
+		PerHost:    cfg.Providers.FetchPerHost,
+		MaxRetries: cfg.Providers.FetchMaxRetries,
+	})
+
 	// Initialize multiple data providers
 	providers := []search.Provider{
-		search.NewLiveProvider(), // Yahoo Finance, ETF.com, JSE
+		search.NewMonitoredProvider("live", live, breaker),
 	}
 
-	// Add Alpha Vantage if API key is provided
-	if cfg.AlphaVantageKey != "" && cfg.AlphaVantageKey != "demo" {
-		providers = append(providers, search.NewAlphaVantageProvider(cfg.AlphaVantageKey))
+	// Add Alpha Vantage if API key is provided (replayed fixtures need no key)
+	if (cfg.AlphaVantageKey != "" && cfg.AlphaVantageKey != "demo") || fixtureMode == search.FixtureModeReplay {
+		alphaVantage := search.NewAlphaVantageProvider(cfg.AlphaVantageKey, httpClient, universe)
+		alphaVantage.SetQuota(search.NewQuotaTracker(cfg.AlphaVantage.QuotaPath,
+			cfg.AlphaVantage.DailyLimit, cfg.AlphaVantage.MinuteLimit))
+		providers = append(providers, search.NewMonitoredProvider("alpha_vantage", alphaVantage, breaker))
 		log.Printf("Alpha Vantage provider enabled")
 	} else {
 		log.Printf("Alpha Vantage provider disabled (no API key). Get free key at https://www.alphavantage.co/support/#api-key")
 	}
 
 	// Combine all providers with intelligent aggregation and caching
-	return search.NewAggregatedProvider(providers...)
+	var cache *search.ETFCache
+	if cfg.Cache.Enabled {
+		cache = search.NewETFCache(cfg.Cache.MaxSize,
+			time.Duration(cfg.Cache.TTLSeconds)*time.Second,
+			time.Duration(cfg.Cache.MaxStalenessSeconds)*time.Second)
+	}
+
+	aggregated := search.NewAggregatedProvider(cache, providers...)
+	aggregated.SetFetchTimeout(breaker.Timeout)
+	aggregated.SetMergePolicy(mergePolicy(cfg))
+
+	return aggregated
+}
+
+// ruleSetInfo describes a registered rule version for the health endpoint
+type ruleSetInfo struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Source        string `json:"source"` // Rule file, or "built-in"
+	EffectiveFrom string `json:"effectiveFrom,omitempty"`
+	EffectiveTo   string `json:"effectiveTo,omitempty"`
 }
 
-func initializeEligibilityEngine() eligibility.Engine {
+// initializeEligibilityEngine registers the built-in rule sets, then the rule
+// files in cfg.Rules.Dir. The engine picks the version in effect on the
+// evaluation date; a file without dates supersedes the built-in of the same name.
+// Rule sets with different names that apply to the same account are combined
+// under cfg.Rules.Policy. Platform lists in cfg.Platforms.Dir are registered
+// last and only apply when a request names their platform.
+func initializeEligibilityEngine(cfg *config.Config) (eligibility.Engine, []ruleSetInfo) {
 	engine := eligibility.NewEngine()
+	engine.SetPolicy(eligibility.CompositionPolicy(cfg.Rules.Policy))
+	registered := make([]ruleSetInfo, 0)
+
+	register := func(rule eligibility.Rule, source string) {
+		engine.RegisterRule(rule)
+		info := ruleSetInfo{Name: rule.Name(), Version: rule.Version(), Source: source}
+		if dated, ok := rule.(eligibility.EffectivePeriod); ok {
+			from, to := dated.EffectivePeriod()
+			if !from.IsZero() {
+				info.EffectiveFrom = from.Format("2006-01-02")
+			}
+			if !to.IsZero() {
+				info.EffectiveTo = to.Format("2006-01-02")
+			}
+		}
+		registered = append(registered, info)
+	}
 
-	// Register eligibility rules
-	engine.RegisterRule(rules.NewTFSASouthAfricaRules())
-	// engine.RegisterRule(rules.NewISAUKRules())
-	// engine.RegisterRule(rules.NewIRAUSRules())
+	register(rules.NewTFSASouthAfricaRules(), "built-in")
+	register(rules.NewISAUKRules(), "built-in")
+	register(rules.NewIRAUSRules(), "built-in")
+	register(rules.NewRothIRAUSRules(), "built-in")
+	register(rules.New401kUSRules(), "built-in")
+	register(rules.NewReg28SouthAfricaRules(), "built-in")
 
-	return engine
+	declarative, err := rules.LoadDeclarativeRules(cfg.Rules.Dir)
+	if err != nil {
+		log.Printf("Some eligibility rule files were not loaded: %v", err)
+	}
+	for _, rule := range declarative {
+		register(rule, rule.Path())
+		log.Printf("Eligibility rules %s (%s) loaded from %s", rule.Name(), rule.Version(), rule.Path())
+	}
+
+	overlays, err := rules.LoadPlatformOverlays(cfg.Platforms.Dir)
+	if err != nil {
+		log.Printf("Some platform lists were not loaded: %v", err)
+	}
+	for _, overlay := range overlays {
+		register(overlay, overlay.Path())
+		log.Printf("Platform list %s (%s) loaded from %s", overlay.Platform(), overlay.Version(), overlay.Path())
+	}
+
+	return engine, registered
+}
+
+func initializeTFSAPlanner(cfg *config.Config) *tfsa.Planner {
+	caps, err := tfsa.LoadCaps(cfg.TFSA.CapsPath)
+	if err != nil {
+		log.Printf("Failed to load TFSA contribution caps, using built-in caps: %v", err)
+		caps = tfsa.DefaultCaps()
+	} else {
+		log.Printf("TFSA contribution caps loaded from %s (%d versions)", caps.Path(), len(caps.Versions()))
+	}
+	return tfsa.NewPlanner(caps)
+}
+
+func initializeCostModel(cfg *config.Config) *cost.Model {
+	schedules, err := cost.LoadSchedules(cfg.Costs.SchedulesPath)
+	if err != nil {
+		log.Printf("Failed to load brokerage schedules, using built-in schedule: %v", err)
+		schedules = cost.DefaultSchedules()
+	} else {
+		log.Printf("Brokerage schedules %s loaded from %s (%d schedules)", schedules.Version(), schedules.Path(), len(schedules.List()))
+	}
+	return cost.NewModel(schedules)
 }
 
 func initializeRankingEngine() ranking.Engine {
@@ -153,6 +394,24 @@ func serveDocumentation(w http.ResponseWriter, r *http.Request) {
         <p><strong>Response:</strong> Ranked list of eligible ETFs with eligibility justifications</p>
     </div>
     
+    <div class="endpoint">
+        <h3>GET /api/v1/eligibility/{ticker or ISIN}?country=&accountType=</h3>
+        <p>Explain one instrument's eligibility for an account; POST /api/v1/eligibility/batch takes a list of identifiers</p>
+        <p><strong>Response:</strong> Full eligibility result with evidence, passed, failed and skipped criteria</p>
+    </div>
+    
+    <div class="endpoint">
+        <h3>POST /api/v1/planner/tfsa</h3>
+        <p>Schedule monthly TFSA contributions within the annual and lifetime caps</p>
+        <p><strong>Response:</strong> Per tax year schedule, remaining headroom, over-contribution penalties and projected tax-free value</p>
+    </div>
+    
+    <div class="endpoint">
+        <h3>POST /api/v1/compliance/reg28/portfolio</h3>
+        <p>Test a weighted set of ETFs against Regulation 28 limits</p>
+        <p><strong>Response:</strong> Limit utilisation per category (offshore, equity, property, commodities, single issuer)</p>
+    </div>
+    
     <div class="endpoint">
         <h3>GET /api/v1/health</h3>
         <p>Health check endpoint</p>
@@ -160,9 +419,9 @@ func serveDocumentation(w http.ResponseWriter, r *http.Request) {
 
     <h2>Supported Countries & Account Types</h2>
     <ul>
-        <li><strong>South Africa (ZA)</strong>: TFSA, Standard</li>
-        <li><strong>United States (US)</strong>: Coming soon</li>
-        <li><strong>United Kingdom (GB)</strong>: Coming soon</li>
+        <li><strong>South Africa (ZA)</strong>: TFSA, Retirement Annuity, Preservation Fund, Standard</li>
+        <li><strong>United States (US)</strong>: IRA, Roth IRA, 401(k), Standard</li>
+        <li><strong>United Kingdom (GB)</strong>: ISA, Standard</li>
     </ul>
 
     <h2>Design Principles</h2>
@@ -8,14 +8,31 @@ type DiscoveryRequest struct {
 	Constraints        Constraints        `json:"constraints"`
 	RankingPreferences RankingPreferences `json:"rankingPreferences"`
 	OutputOptions      OutputOptions      `json:"outputOptions"`
+	AsOf               string             `json:"asOf,omitempty" validate:"omitempty,datetime=2006-01-02"` // Apply the rule versions in effect on this date
+}
+
+// EligibilityQuery selects the account an eligibility lookup is evaluated for
+type EligibilityQuery struct {
+	Country     string `json:"country" validate:"required,iso3166_1_alpha2"`
+	AccountType string `json:"accountType" validate:"required"`
+	Platform    string `json:"platform,omitempty"`
+	AsOf        string `json:"asOf,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// EligibilityBatchRequest asks for the eligibility of several instruments
+type EligibilityBatchRequest struct {
+	Identifiers []string `json:"identifiers" validate:"required,min=1,max=50,dive,required"` // Tickers or ISINs
+	EligibilityQuery
 }
 
 type InvestorProfile struct {
-	Country          string `json:"country" validate:"required,iso3166_1_alpha2"`
-	AccountType      string `json:"accountType" validate:"required"`
-	Currency         string `json:"currency" validate:"required,iso4217"`
-	RiskTolerance    string `json:"riskTolerance" validate:"omitempty,oneof=conservative moderate aggressive"`
-	TimeHorizonYears int    `json:"timeHorizonYears" validate:"omitempty,min=1,max=50"`
+	Country          string  `json:"country" validate:"required,iso3166_1_alpha2"`
+	AccountType      string  `json:"accountType" validate:"required"`
+	Currency         string  `json:"currency" validate:"required,iso4217"`
+	RiskTolerance    string  `json:"riskTolerance" validate:"omitempty,oneof=conservative moderate aggressive"`
+	TimeHorizonYears int     `json:"timeHorizonYears" validate:"omitempty,min=1,max=50"`
+	Platform         string  `json:"platform,omitempty"`                                   // Investment platform, e.g. "easyequities"; limits results to what it offers
+	InvestmentAmount float64 `json:"investmentAmount,omitempty" validate:"omitempty,gt=0"` // In Currency; sizes brokerage and platform fees
 }
 
 type ExposureRequest struct {
@@ -50,8 +67,14 @@ type Constraints struct {
 }
 
 type RankingPreferences struct {
-	Priority  []string           `json:"priority" validate:"omitempty,dive,oneof=lowest_fees tracking_accuracy liquidity diversification tax_efficiency"`
-	Weighting map[string]float64 `json:"weighting"`
+	Priority  []string           `json:"priority" validate:"omitempty,dive,oneof=lowest_fees tracking_accuracy liquidity fund_size diversification tax_efficiency"`
+	Weighting map[string]float64 `json:"weighting"` // Keyed by the priority names; must sum to 1.0
+}
+
+// RankingWeights are the component weights the ranking used
+type RankingWeights struct {
+	Method  string             `json:"method"` // "default", "rank_order_centroid" or "custom"
+	Weights map[string]float64 `json:"weights"`
 }
 
 type OutputOptions struct {
@@ -64,13 +87,16 @@ type OutputOptions struct {
 
 // DiscoveryResponse is the API output
 type DiscoveryResponse struct {
-	RequestID    string        `json:"requestId"`
-	Results      []ETFResult   `json:"results"`
-	Alternatives []ETFResult   `json:"alternatives,omitempty"`
-	Summary      SearchSummary `json:"summary"`
-	Warnings     []Warning     `json:"warnings,omitempty"`
-	GeneratedAt  string        `json:"generatedAt"`
-	CacheHit     bool          `json:"cacheHit"`
+	RequestID      string         `json:"requestId"`
+	Results        []ETFResult    `json:"results"`
+	Alternatives   []ETFResult    `json:"alternatives,omitempty"`
+	Summary        SearchSummary  `json:"summary"`
+	Warnings       []Warning      `json:"warnings,omitempty"`
+	RankingWeights RankingWeights `json:"rankingWeights"`
+	GeneratedAt    string         `json:"generatedAt"`
+	CacheHit       bool           `json:"cacheHit"`
+	Stale          bool           `json:"stale"`              // Served from expired cache while refreshing
+	DataAsOf       string         `json:"dataAsOf,omitempty"` // When stale data was fetched (RFC3339)
 }
 
 type ETFResult struct {
@@ -101,13 +127,21 @@ type ETFResult struct {
 	RankingScore float64 `json:"rankingScore"`
 	Rank         int     `json:"rank"`
 
+	// Estimated dividend tax drag for the investor's country and account
+	TaxDrag *TaxDrag `json:"taxDrag,omitempty"`
+
+	// Estimated all-in holding cost for the investor's amount, period and broker
+	Costs *CostBreakdown `json:"costs,omitempty"`
+
 	// Breakdown (optional based on OutputOptions)
 	AssetBreakdown      *AssetBreakdown      `json:"assetBreakdown,omitempty"`
 	GeographicBreakdown *GeographicBreakdown `json:"geographicBreakdown,omitempty"`
 	TopHoldings         []HoldingInfo        `json:"topHoldings,omitempty"`
 
 	// Sources
-	DataSources []SourceReference `json:"dataSources,omitempty"`
+	DataSources     []SourceReference          `json:"dataSources,omitempty"`
+	FieldProvenance map[string]SourceReference `json:"fieldProvenance,omitempty"`
+	DataConflicts   []string                   `json:"dataConflicts,omitempty"`
 }
 
 type EligibilityDetail struct {
@@ -119,6 +153,62 @@ type EligibilityDetail struct {
 	RulesFailed   []string `json:"rulesFailed,omitempty"`
 	Warnings      []string `json:"warnings,omitempty"`
 	RuleVersion   string   `json:"ruleVersion,omitempty"`
+	AsOf          string   `json:"asOf,omitempty"` // Date whose rule versions were applied
+
+	// Per rule set verdicts when several rule sets were combined
+	RuleResults []RuleResult `json:"ruleResults,omitempty"`
+
+	EvidenceCompleteness float64        `json:"evidenceCompleteness"` // 0-100, criteria backed by sourced, definite evidence
+	Evidence             []EvidenceItem `json:"evidence,omitempty"`
+}
+
+// EvidenceItem is the audit record for one eligibility criterion
+type EvidenceItem struct {
+	Rule      string           `json:"rule,omitempty"`
+	Criterion string           `json:"criterion"`
+	Expected  string           `json:"expected"`
+	Actual    string           `json:"actual"`
+	Result    string           `json:"result"` // "pass", "fail", "unknown"
+	Source    *SourceReference `json:"source,omitempty"`
+}
+
+type RuleResult struct {
+	Rule       string `json:"rule"`
+	Version    string `json:"version"`
+	Status     string `json:"status"`
+	Confidence string `json:"confidence"`
+}
+
+type TaxDrag struct {
+	FundDomicile      string   `json:"fundDomicile"`
+	DividendYield     float64  `json:"dividendYield"` // % per year
+	YieldAssumed      bool     `json:"yieldAssumed"`
+	FundLevelRate     float64  `json:"fundLevelRate"`     // % withheld before dividends reach the fund
+	InvestorLevelRate float64  `json:"investorLevelRate"` // % withheld or taxed on distributions to the investor
+	EffectiveRate     float64  `json:"effectiveRate"`     // Combined % of dividends lost
+	AnnualDrag        float64  `json:"annualDrag"`        // % of fund value per year
+	Confidence        string   `json:"confidence"`
+	Notes             []string `json:"notes,omitempty"`
+	TableVersion      string   `json:"tableVersion"`
+}
+
+type CostBreakdown struct {
+	Amount          float64  `json:"amount"`
+	Currency        string   `json:"currency"`
+	HoldingYears    int      `json:"holdingYears"`
+	TER             float64  `json:"ter"` // % per year
+	TERAssumed      bool     `json:"terAssumed"`
+	Spread          float64  `json:"spread"` // % per year, entry and exit spread over the holding period
+	SpreadAssumed   bool     `json:"spreadAssumed"`
+	FX              float64  `json:"fx"`
+	Brokerage       float64  `json:"brokerage"`
+	PlatformFee     float64  `json:"platformFee"`
+	EffectiveCost   float64  `json:"effectiveCost"` // All-in % per year
+	AnnualCost      float64  `json:"annualCost"`
+	TotalCost       float64  `json:"totalCost"`
+	Schedule        string   `json:"schedule"`
+	ScheduleVersion string   `json:"scheduleVersion"`
+	Notes           []string `json:"notes,omitempty"`
 }
 
 type AssetBreakdown struct {
@@ -126,6 +216,7 @@ type AssetBreakdown struct {
 	Bonds       float64 `json:"bonds"`
 	Cash        float64 `json:"cash"`
 	Commodities float64 `json:"commodities"`
+	RealEstate  float64 `json:"realEstate"`
 	Other       float64 `json:"other"`
 }
 
@@ -152,14 +243,80 @@ type SearchSummary struct {
 	TotalEligible      int      `json:"totalEligible"`
 	TotalIneligible    int      `json:"totalIneligible"`
 	TotalUnknown       int      `json:"totalUnknown"`
+	TotalNotOffered    int      `json:"totalNotOffered,omitempty"` // Eligible, but not offered on the requested platform
 	SearchDurationMs   int64    `json:"searchDurationMs"`
-	DataSourcesQueried []string `json:"dataSourcesQueried"`
+	DataSourcesQueried []string `json:"dataSourcesQueried"` // Sources actually called (answered or failed)
+
+	DataSources []DataSourceStatus `json:"dataSources,omitempty"`
+}
+
+// DataSourceStatus reports what one data source did for the search
+type DataSourceStatus struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"` // "answered", "failed", "skipped"
+	Results    int    `json:"results"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
 }
 
 type Warning struct {
 	Code     string `json:"code"`
 	Message  string `json:"message"`
 	Severity string `json:"severity"` // "info", "warning", "critical"
+
+	// Set on warnings about a single result
+	Ticker       string           `json:"ticker,omitempty"`
+	Alternatives []AlternativeETF `json:"alternatives,omitempty"`
+}
+
+// AlternativeETF is a suggested replacement that avoids a warning's issue
+type AlternativeETF struct {
+	Ticker   string  `json:"ticker"`
+	Name     string  `json:"name"`
+	ISIN     string  `json:"isin,omitempty"`
+	Exchange string  `json:"exchange"`
+	Domicile string  `json:"domicile"`
+	TER      float64 `json:"ter"`
+}
+
+// PortfolioRequest is a set of ETFs and weights to test against portfolio limits
+type PortfolioRequest struct {
+	Holdings []PortfolioHolding `json:"holdings" validate:"required,min=1,max=50,dive"`
+}
+
+// PortfolioHolding identifies an ETF by ticker or ISIN. Breakdowns, when given,
+// override catalogued data for that ETF.
+type PortfolioHolding struct {
+	Ticker              string               `json:"ticker" validate:"required"`
+	Weight              float64              `json:"weight" validate:"gt=0,lte=100"` // Percentage of portfolio
+	AssetBreakdown      *AssetBreakdown      `json:"assetBreakdown,omitempty"`
+	GeographicBreakdown *GeographicBreakdown `json:"geographicBreakdown,omitempty"`
+	TopHoldings         []HoldingInfo        `json:"topHoldings,omitempty"`
+}
+
+// TFSAPlanRequest asks for a contribution schedule for a South African
+// tax-free savings account. InvestorProfile.TimeHorizonYears sets the horizon.
+type TFSAPlanRequest struct {
+	InvestorProfile     InvestorProfile       `json:"investorProfile" validate:"required"`
+	PriorContributions  []TaxYearContribution `json:"priorContributions" validate:"max=50,dive"`
+	CurrentValue        *float64              `json:"currentValue,omitempty" validate:"omitempty,min=0"` // Value of existing TFSAs
+	MonthlyContribution float64               `json:"monthlyContribution" validate:"gt=0"`
+	ExpectedReturn      *float64              `json:"expectedReturn,omitempty" validate:"omitempty,min=-20,max=30"` // % per year before fund costs
+	Holdings            []PlannedHolding      `json:"holdings" validate:"required,min=1,max=20,dive"`
+	StartDate           string                `json:"startDate,omitempty" validate:"omitempty,datetime=2006-01-02"` // First contribution month; default this month
+}
+
+// TaxYearContribution is the total paid into TFSAs in one tax year, named by
+// the year it ends: 2026 runs from March 2025 to February 2026
+type TaxYearContribution struct {
+	TaxYear int     `json:"taxYear" validate:"min=2016,max=2100"`
+	Amount  float64 `json:"amount" validate:"min=0"`
+}
+
+// PlannedHolding is an ETF and its share of each contribution
+type PlannedHolding struct {
+	Ticker string  `json:"ticker" validate:"required"`
+	Weight float64 `json:"weight" validate:"gt=0,lte=100"`
 }
 
 // ErrorResponse for error cases
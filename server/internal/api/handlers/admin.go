@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminAuth guards the admin endpoints. They fail closed: with no key
+// configured, requests are refused unless Open is set for local development.
+type AdminAuth struct {
+	Key  string
+	Open bool // Allow unauthenticated admin requests when no key is set
+}
+
+// authorize checks the X-Admin-Key header against the configured key and
+// writes the error response when the request is refused
+func (a AdminAuth) authorize(w http.ResponseWriter, r *http.Request, requestID string) bool {
+	if a.Key == "" {
+		if a.Open {
+			return true
+		}
+		respondError(w, requestID, http.StatusForbidden, "ADMIN_DISABLED",
+			"Admin endpoints are disabled", "Set ADMIN_API_KEY, or ADMIN_OPEN=true for local development")
+		return false
+	}
+
+	provided := r.Header.Get("X-Admin-Key")
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(a.Key)) != 1 {
+		respondError(w, requestID, http.StatusUnauthorized, "UNAUTHORIZED",
+			"A valid X-Admin-Key header is required", "")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"upstonk/internal/service/catalog"
+)
+
+// maxImportSize caps uploaded catalog files at 10 MB
+const maxImportSize = 10 << 20
+
+type CatalogHandler struct {
+	importer *catalog.Importer
+	admin    AdminAuth
+}
+
+func NewCatalogHandler(importer *catalog.Importer, admin AdminAuth) *CatalogHandler {
+	return &CatalogHandler{
+		importer: importer,
+		admin:    admin,
+	}
+}
+
+// HandleImport ingests a CSV or JSON file into the catalog: POST /api/v1/admin/catalog/import
+// The file is sent either as multipart field "file" or as the raw request body.
+// Query parameters: format (csv|json), source, reliability, dryRun.
+func (h *CatalogHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New().String()
+
+	if !h.admin.authorize(w, r, requestID) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var body io.Reader = r.Body
+	formatHint := r.Header.Get("Content-Type")
+	source := r.URL.Query().Get("source")
+
+	if file, header, err := r.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+		formatHint = header.Filename
+		if source == "" {
+			source = header.Filename
+		}
+	}
+
+	if format := r.URL.Query().Get("format"); format != "" {
+		formatHint = "." + format
+	}
+
+	format, err := catalog.FormatFromName(formatHint)
+	if err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_FORMAT",
+			"Unable to determine import format", err.Error())
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+
+	report, err := h.importer.Import(body, format, catalog.ImportOptions{
+		SourceName:  source,
+		Reliability: r.URL.Query().Get("reliability"),
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "IMPORT_FAILED",
+			"Catalog import failed", err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if report.Imported == 0 && report.Rejected > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+
+	respondJSON(w, status, report)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"upstonk/internal/api/dto"
+	"upstonk/internal/domain"
+	"upstonk/internal/service/catalog"
+	"upstonk/internal/service/reg28"
+)
+
+type ComplianceHandler struct {
+	catalog   *catalog.Store
+	limits    reg28.Limits
+	validator *validator.Validate
+}
+
+// NewComplianceHandler resolves portfolio tickers from store, which may be nil
+// when the catalog is disabled; holdings must then carry their own breakdowns
+func NewComplianceHandler(store *catalog.Store, limits reg28.Limits) *ComplianceHandler {
+	return &ComplianceHandler{
+		catalog:   store,
+		limits:    limits,
+		validator: validator.New(),
+	}
+}
+
+type reg28PortfolioResponse struct {
+	RequestID string `json:"requestId"`
+	reg28.Report
+	GeneratedAt string `json:"generatedAt"`
+}
+
+// HandleReg28Portfolio tests a weighted set of ETFs against Regulation 28 limits:
+// POST /api/v1/compliance/reg28/portfolio
+func (h *ComplianceHandler) HandleReg28Portfolio(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New().String()
+
+	var req dto.PortfolioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_JSON",
+			"Failed to parse request body", err.Error())
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "VALIDATION_ERROR",
+			"Request validation failed", formatValidationErrors(err))
+		return
+	}
+
+	if err := validatePortfolio(req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), "")
+		return
+	}
+
+	holdings := make([]reg28.Holding, 0, len(req.Holdings))
+	for _, holding := range req.Holdings {
+		holdings = append(holdings, h.resolve(holding))
+	}
+
+	respondJSON(w, http.StatusOK, reg28PortfolioResponse{
+		RequestID:   requestID,
+		Report:      reg28.CheckPortfolio(holdings, h.limits),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// validatePortfolio requires unique tickers whose weights sum to 100%
+func validatePortfolio(req dto.PortfolioRequest) error {
+	seen := make(map[string]bool, len(req.Holdings))
+	sum := 0.0
+	for _, holding := range req.Holdings {
+		ticker := strings.ToUpper(strings.TrimSpace(holding.Ticker))
+		if seen[ticker] {
+			return fmt.Errorf("duplicate holding: %s", holding.Ticker)
+		}
+		seen[ticker] = true
+		sum += holding.Weight
+	}
+	if sum < 99 || sum > 101 {
+		return fmt.Errorf("holding weights must sum to 100, got %.2f", sum)
+	}
+	return nil
+}
+
+// resolve looks the holding up in the catalog and applies any breakdowns sent
+// with the request
+func (h *ComplianceHandler) resolve(holding dto.PortfolioHolding) reg28.Holding {
+	etf := domain.ETF{Ticker: holding.Ticker}
+	resolved := false
+	if h.catalog != nil {
+		if catalogued, ok := h.catalog.Get(holding.Ticker); ok {
+			etf, resolved = catalogued, true
+		}
+	}
+
+	if b := holding.AssetBreakdown; b != nil {
+		etf.AssetExposure = domain.AssetExposure{
+			Equities:    b.Equities,
+			Bonds:       b.Bonds,
+			Cash:        b.Cash,
+			Commodities: b.Commodities,
+			RealEstate:  b.RealEstate,
+			Other:       b.Other,
+		}
+		resolved = true
+	}
+	if b := holding.GeographicBreakdown; b != nil {
+		etf.GeographicExposure = domain.GeographicExposure{Regions: b.Regions, Countries: b.Countries}
+		resolved = true
+	}
+	if len(holding.TopHoldings) > 0 {
+		etf.TopHoldings = make([]domain.Holding, 0, len(holding.TopHoldings))
+		for _, top := range holding.TopHoldings {
+			etf.TopHoldings = append(etf.TopHoldings, domain.Holding{Name: top.Name, Ticker: top.Ticker, Weight: top.Weight})
+		}
+		resolved = true
+	}
+
+	return reg28.Holding{ETF: etf, Weight: holding.Weight, Resolved: resolved}
+}
@@ -14,20 +14,28 @@ import (
 
 	"upstonk/internal/api/dto"
 	"upstonk/internal/service/discovery"
+	"upstonk/internal/service/ranking"
 )
 
 type DiscoveryHandler struct {
-	service   *discovery.Service
-	validator *validator.Validate
+	service      *discovery.Service
+	validator    *validator.Validate
+	healthChecks map[string]func() interface{}
 }
 
 func NewDiscoveryHandler(service *discovery.Service) *DiscoveryHandler {
 	return &DiscoveryHandler{
-		service:   service,
-		validator: validator.New(),
+		service:      service,
+		validator:    validator.New(),
+		healthChecks: make(map[string]func() interface{}),
 	}
 }
 
+// RegisterHealthCheck adds a named component report to the health endpoint
+func (h *DiscoveryHandler) RegisterHealthCheck(name string, report func() interface{}) {
+	h.healthChecks[name] = report
+}
+
 // HandleDiscovery is the main endpoint: POST /api/v1/discover
 func (h *DiscoveryHandler) HandleDiscovery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -43,7 +51,7 @@ func (h *DiscoveryHandler) HandleDiscovery(w http.ResponseWriter, r *http.Reques
 	// Parse request body
 	var req dto.DiscoveryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, requestID, http.StatusBadRequest, "INVALID_JSON",
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_JSON",
 			"Failed to parse request body", err.Error())
 		return
 	}
@@ -51,14 +59,14 @@ func (h *DiscoveryHandler) HandleDiscovery(w http.ResponseWriter, r *http.Reques
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
 		validationErrors := h.formatValidationErrors(err)
-		h.respondError(w, requestID, http.StatusBadRequest, "VALIDATION_ERROR",
+		respondError(w, requestID, http.StatusBadRequest, "VALIDATION_ERROR",
 			"Request validation failed", validationErrors)
 		return
 	}
 
 	// Business validation
 	if err := h.validateBusinessRules(&req); err != nil {
-		h.respondError(w, requestID, http.StatusBadRequest, "INVALID_REQUEST",
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_REQUEST",
 			err.Error(), "")
 		return
 	}
@@ -72,16 +80,19 @@ func (h *DiscoveryHandler) HandleDiscovery(w http.ResponseWriter, r *http.Reques
 
 	// Build response
 	response := dto.DiscoveryResponse{
-		RequestID:    requestID,
-		Results:      result.Results,
-		Alternatives: result.Alternatives,
-		Summary:      result.Summary,
-		Warnings:     result.Warnings,
-		GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
-		CacheHit:     result.CacheHit,
+		RequestID:      requestID,
+		Results:        result.Results,
+		Alternatives:   result.Alternatives,
+		Summary:        result.Summary,
+		Warnings:       result.Warnings,
+		RankingWeights: result.Weights,
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		CacheHit:       result.CacheHit,
+		Stale:          result.Stale,
+		DataAsOf:       result.DataAsOf,
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	respondJSON(w, http.StatusOK, response)
 }
 
 func (h *DiscoveryHandler) validateBusinessRules(req *dto.DiscoveryRequest) error {
@@ -109,15 +120,9 @@ func (h *DiscoveryHandler) validateBusinessRules(req *dto.DiscoveryRequest) erro
 		return fmt.Errorf("at least one exposure criterion must be specified")
 	}
 
-	// Validate ranking weights sum to 1.0 if provided
-	if len(req.RankingPreferences.Weighting) > 0 {
-		sum := 0.0
-		for _, weight := range req.RankingPreferences.Weighting {
-			sum += weight
-		}
-		if sum < 0.99 || sum > 1.01 {
-			return fmt.Errorf("ranking weights must sum to 1.0, got %.2f", sum)
-		}
+	// Validate ranking weight keys, ranges and sum, and priority duplicates
+	if _, err := ranking.ResolveWeights(req.RankingPreferences); err != nil {
+		return err
 	}
 
 	// Set defaults for output options
@@ -132,9 +137,11 @@ func (h *DiscoveryHandler) isAccountTypeSupported(country, accountType string) b
 	// Registry of supported account types per country
 	supportedCombinations := map[string]map[string]bool{
 		"ZA": {
-			"tfsa":     true,
-			"standard": true,
-			"ira":      false,
+			"tfsa":               true,
+			"retirement_annuity": true,
+			"preservation_fund":  true,
+			"standard":           true,
+			"ira":                false,
 		},
 		"US": {
 			"ira":      true,
@@ -160,22 +167,25 @@ func (h *DiscoveryHandler) handleServiceError(w http.ResponseWriter, requestID s
 	// Type-switch on error to provide specific error codes
 	switch err.(type) {
 	case *discovery.NoResultsError:
-		h.respondError(w, requestID, http.StatusNotFound, "NO_RESULTS",
+		respondError(w, requestID, http.StatusNotFound, "NO_RESULTS",
 			"No ETFs found matching the criteria", err.Error())
 	case *discovery.UnsupportedCountryError:
-		h.respondError(w, requestID, http.StatusBadRequest, "UNSUPPORTED_COUNTRY",
+		respondError(w, requestID, http.StatusBadRequest, "UNSUPPORTED_COUNTRY",
 			"Country not supported", err.Error())
 	case *discovery.DataSourceError:
-		h.respondError(w, requestID, http.StatusServiceUnavailable, "DATA_SOURCE_ERROR",
+		respondError(w, requestID, http.StatusServiceUnavailable, "DATA_SOURCE_ERROR",
 			"Unable to retrieve data from external sources", err.Error())
 	default:
-		h.respondError(w, requestID, http.StatusInternalServerError, "INTERNAL_ERROR",
+		respondError(w, requestID, http.StatusInternalServerError, "INTERNAL_ERROR",
 			"An internal error occurred", "Please try again later")
 	}
 }
 
 func (h *DiscoveryHandler) formatValidationErrors(err error) string {
+	return formatValidationErrors(err)
+}
 
+func formatValidationErrors(err error) string {
 	validationErrors, ok := err.(validator.ValidationErrors)
 	if !ok {
 		return err.Error()
@@ -203,27 +213,6 @@ func (h *DiscoveryHandler) formatValidationErrors(err error) string {
 	return string(result)
 }
 
-func (h *DiscoveryHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
-}
-
-func (h *DiscoveryHandler) respondError(w http.ResponseWriter, requestID string, status int, code, message, details string) {
-	errorResp := dto.ErrorResponse{
-		Error:     code,
-		Message:   message,
-		Code:      code,
-		RequestID: requestID,
-	}
-
-	if details != "" {
-		errorResp.Details = map[string]string{"details": details}
-	}
-
-	h.respondJSON(w, status, errorResp)
-}
-
 // HandleHealth is a simple health check endpoint
 func (h *DiscoveryHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
@@ -232,7 +221,10 @@ func (h *DiscoveryHandler) HandleHealth(w http.ResponseWriter, r *http.Request)
 		"service":   "upstonk-api",
 		"version":   "1.0.0",
 	}
-	h.respondJSON(w, http.StatusOK, response)
+	for name, report := range h.healthChecks {
+		response[name] = report()
+	}
+	respondJSON(w, http.StatusOK, response)
 }
 
 // HandleTopPerformers returns top performing stocks/ETFs based on asset class or investment vehicle
@@ -246,7 +238,7 @@ func (h *DiscoveryHandler) HandleTopPerformers(w http.ResponseWriter, r *http.Re
 	vars := mux.Vars(r)
 	assetType := vars["type"]
 	if assetType == "" {
-		h.respondError(w, requestID, http.StatusBadRequest, "INVALID_PARAMETER",
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_PARAMETER",
 			"Type parameter is required", "Use: /api/v1/discover/{assetClass or investmentVehicle}")
 		return
 	}
@@ -267,7 +259,7 @@ func (h *DiscoveryHandler) HandleTopPerformers(w http.ResponseWriter, r *http.Re
 		investmentVehicles = []string{"etf"}
 		assetClass = "equity" // Default for ETFs
 	default:
-		h.respondError(w, requestID, http.StatusBadRequest, "INVALID_TYPE",
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_TYPE",
 			fmt.Sprintf("Unknown type: %s", assetType),
 			"Valid types: equity, bond, etf, stock")
 		return
@@ -302,14 +294,17 @@ func (h *DiscoveryHandler) HandleTopPerformers(w http.ResponseWriter, r *http.Re
 
 	// Build response
 	response := dto.DiscoveryResponse{
-		RequestID:    requestID,
-		Results:      result.Results,
-		Alternatives: result.Alternatives,
-		Summary:      result.Summary,
-		Warnings:     result.Warnings,
-		GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
-		CacheHit:     result.CacheHit,
+		RequestID:      requestID,
+		Results:        result.Results,
+		Alternatives:   result.Alternatives,
+		Summary:        result.Summary,
+		Warnings:       result.Warnings,
+		RankingWeights: result.Weights,
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		CacheHit:       result.CacheHit,
+		Stale:          result.Stale,
+		DataAsOf:       result.DataAsOf,
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	respondJSON(w, http.StatusOK, response)
 }
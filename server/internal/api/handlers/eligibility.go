@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"upstonk/internal/api/dto"
+	"upstonk/internal/domain"
+	"upstonk/internal/service/discovery"
+)
+
+// instrumentEligibility is the full eligibility result for one identifier
+type instrumentEligibility struct {
+	Identifier  string                    `json:"identifier"`
+	Found       bool                      `json:"found"`
+	Ticker      string                    `json:"ticker,omitempty"`
+	ISIN        string                    `json:"isin,omitempty"`
+	Name        string                    `json:"name,omitempty"`
+	Exchange    string                    `json:"exchange,omitempty"`
+	Eligibility *domain.EligibilityResult `json:"eligibility,omitempty"`
+}
+
+type eligibilityResponse struct {
+	RequestID   string `json:"requestId"`
+	Country     string `json:"country"`
+	AccountType string `json:"accountType"`
+	instrumentEligibility
+	GeneratedAt string `json:"generatedAt"`
+}
+
+type eligibilityBatchResponse struct {
+	RequestID   string                  `json:"requestId"`
+	Country     string                  `json:"country"`
+	AccountType string                  `json:"accountType"`
+	Results     []instrumentEligibility `json:"results"`
+	GeneratedAt string                  `json:"generatedAt"`
+}
+
+// HandleEligibility explains one instrument's eligibility:
+// GET /api/v1/eligibility/{identifier}?country=&accountType=[&platform=&asOf=]
+func (h *DiscoveryHandler) HandleEligibility(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New().String()
+	ctx, cancel := context.WithTimeout(context.WithValue(r.Context(), "requestID", requestID), 30*time.Second)
+	defer cancel()
+
+	params := r.URL.Query()
+	query := dto.EligibilityQuery{
+		Country:     strings.ToUpper(strings.TrimSpace(params.Get("country"))),
+		AccountType: strings.ToLower(strings.TrimSpace(params.Get("accountType"))),
+		Platform:    params.Get("platform"),
+		AsOf:        params.Get("asOf"),
+	}
+	if !h.validateEligibilityQuery(w, requestID, query) {
+		return
+	}
+
+	identifier := mux.Vars(r)["identifier"]
+	results, err := h.service.ExplainEligibility(ctx, []string{identifier}, query)
+	if err != nil {
+		h.handleServiceError(w, requestID, err)
+		return
+	}
+
+	result := toInstrumentEligibility(results[0])
+	if !result.Found {
+		respondError(w, requestID, http.StatusNotFound, "NOT_FOUND",
+			fmt.Sprintf("No instrument found for %s", identifier), "Use a ticker the catalog or live universe knows, or the ISIN of a catalogued fund")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, eligibilityResponse{
+		RequestID:             requestID,
+		Country:               query.Country,
+		AccountType:           query.AccountType,
+		instrumentEligibility: result,
+		GeneratedAt:           time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// HandleEligibilityBatch explains the eligibility of several instruments:
+// POST /api/v1/eligibility/batch. Unresolved identifiers are reported with found=false.
+func (h *DiscoveryHandler) HandleEligibilityBatch(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New().String()
+	ctx, cancel := context.WithTimeout(context.WithValue(r.Context(), "requestID", requestID), 30*time.Second)
+	defer cancel()
+
+	var req dto.EligibilityBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_JSON",
+			"Failed to parse request body", err.Error())
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "VALIDATION_ERROR",
+			"Request validation failed", h.formatValidationErrors(err))
+		return
+	}
+	if !h.validateEligibilityQuery(w, requestID, req.EligibilityQuery) {
+		return
+	}
+
+	results, err := h.service.ExplainEligibility(ctx, req.Identifiers, req.EligibilityQuery)
+	if err != nil {
+		h.handleServiceError(w, requestID, err)
+		return
+	}
+
+	response := eligibilityBatchResponse{
+		RequestID:   requestID,
+		Country:     req.Country,
+		AccountType: req.AccountType,
+		Results:     make([]instrumentEligibility, 0, len(results)),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, result := range results {
+		response.Results = append(response.Results, toInstrumentEligibility(result))
+	}
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *DiscoveryHandler) validateEligibilityQuery(w http.ResponseWriter, requestID string, query dto.EligibilityQuery) bool {
+	if err := h.validator.Struct(query); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "VALIDATION_ERROR",
+			"Request validation failed", h.formatValidationErrors(err))
+		return false
+	}
+	if !h.isAccountTypeSupported(query.Country, query.AccountType) {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_REQUEST",
+			fmt.Sprintf("account type '%s' is not supported for country '%s'", query.AccountType, query.Country), "")
+		return false
+	}
+	return true
+}
+
+func toInstrumentEligibility(result discovery.InstrumentEligibility) instrumentEligibility {
+	out := instrumentEligibility{Identifier: result.Identifier}
+	if result.ETF == nil {
+		return out
+	}
+	out.Found = true
+	out.Ticker = result.ETF.Ticker
+	out.ISIN = result.ETF.ISIN
+	out.Name = result.ETF.Name
+	out.Exchange = result.ETF.Exchange
+	out.Eligibility = result.Eligibility
+	return out
+}
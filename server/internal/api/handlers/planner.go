@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"upstonk/internal/api/dto"
+	"upstonk/internal/service/discovery"
+	"upstonk/internal/service/tfsa"
+)
+
+type PlannerHandler struct {
+	service   *discovery.Service
+	planner   *tfsa.Planner
+	validator *validator.Validate
+}
+
+// NewPlannerHandler checks planned holdings' eligibility through service and
+// schedules contributions with planner
+func NewPlannerHandler(service *discovery.Service, planner *tfsa.Planner) *PlannerHandler {
+	return &PlannerHandler{
+		service:   service,
+		planner:   planner,
+		validator: validator.New(),
+	}
+}
+
+type tfsaPlanResponse struct {
+	RequestID   string `json:"requestId"`
+	RuleVersion string `json:"ruleVersion,omitempty"` // TFSA eligibility rules the holdings were checked against
+	tfsa.Plan
+	Holdings    []plannedHoldingStatus `json:"holdings"`
+	GeneratedAt string                 `json:"generatedAt"`
+}
+
+type plannedHoldingStatus struct {
+	Ticker     string  `json:"ticker"`
+	Weight     float64 `json:"weight"`
+	Found      bool    `json:"found"`
+	Name       string  `json:"name,omitempty"`
+	Status     string  `json:"status,omitempty"`
+	IsEligible bool    `json:"isEligible"`
+	TER        float64 `json:"ter,omitempty"`
+}
+
+// HandleTFSAPlan schedules monthly TFSA contributions within the annual and
+// lifetime caps: POST /api/v1/planner/tfsa
+func (h *PlannerHandler) HandleTFSAPlan(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New().String()
+	ctx, cancel := context.WithTimeout(context.WithValue(r.Context(), "requestID", requestID), 30*time.Second)
+	defer cancel()
+
+	var req dto.TFSAPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_JSON",
+			"Failed to parse request body", err.Error())
+		return
+	}
+	req.InvestorProfile.Country = strings.ToUpper(req.InvestorProfile.Country)
+	req.InvestorProfile.AccountType = strings.ToLower(req.InvestorProfile.AccountType)
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "VALIDATION_ERROR",
+			"Request validation failed", formatValidationErrors(err))
+		return
+	}
+
+	start := time.Now().UTC()
+	if req.StartDate != "" {
+		start, _ = time.Parse("2006-01-02", req.StartDate)
+	}
+	if err := validatePlan(req, start); err != nil {
+		respondError(w, requestID, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), "")
+		return
+	}
+
+	input := tfsa.PlanInput{
+		CurrentValue:   req.CurrentValue,
+		Monthly:        req.MonthlyContribution,
+		Years:          req.InvestorProfile.TimeHorizonYears,
+		ExpectedReturn: tfsa.DefaultExpectedReturn,
+		Start:          start,
+	}
+	if req.ExpectedReturn != nil {
+		input.ExpectedReturn = *req.ExpectedReturn
+	}
+	for _, contribution := range req.PriorContributions {
+		input.Prior = append(input.Prior, tfsa.Contribution{TaxYear: contribution.TaxYear, Amount: contribution.Amount})
+	}
+
+	holdings, ruleVersion, warnings := h.resolveHoldings(ctx, req, start)
+	for _, holding := range holdings {
+		input.Holdings = append(input.Holdings, tfsa.Holding{Ticker: holding.Ticker, Weight: holding.Weight, TER: holding.TER})
+	}
+
+	plan := h.planner.Plan(input)
+	plan.Warnings = append(plan.Warnings, warnings...)
+
+	respondJSON(w, http.StatusOK, tfsaPlanResponse{
+		RequestID:   requestID,
+		RuleVersion: ruleVersion,
+		Plan:        plan,
+		Holdings:    holdings,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// resolveHoldings checks each holding's TFSA eligibility and TER. Planning
+// goes ahead without them when the data sources cannot be reached.
+func (h *PlannerHandler) resolveHoldings(ctx context.Context, req dto.TFSAPlanRequest, start time.Time) ([]plannedHoldingStatus, string, []string) {
+	holdings := make([]plannedHoldingStatus, 0, len(req.Holdings))
+	tickers := make([]string, 0, len(req.Holdings))
+	for _, holding := range req.Holdings {
+		holdings = append(holdings, plannedHoldingStatus{Ticker: holding.Ticker, Weight: holding.Weight})
+		tickers = append(tickers, holding.Ticker)
+	}
+
+	results, err := h.service.ExplainEligibility(ctx, tickers, dto.EligibilityQuery{
+		Country:     "ZA",
+		AccountType: "tfsa",
+		Platform:    req.InvestorProfile.Platform,
+		AsOf:        start.Format("2006-01-02"),
+	})
+	if err != nil {
+		return holdings, "", []string{fmt.Sprintf("Could not verify holdings' TFSA eligibility or costs: %v", err)}
+	}
+
+	var ruleVersion string
+	var warnings []string
+	for i, result := range results {
+		if result.ETF == nil {
+			warnings = append(warnings, fmt.Sprintf("%s was not found - its TFSA eligibility and costs are unknown", holdings[i].Ticker))
+			continue
+		}
+		holdings[i].Found = true
+		holdings[i].Name = result.ETF.Name
+		holdings[i].TER = result.ETF.TER
+		holdings[i].Status = string(result.Eligibility.Status)
+		holdings[i].IsEligible = result.Eligibility.IsEligible
+		if ruleVersion == "" {
+			ruleVersion = result.Eligibility.RuleVersion
+		}
+		if !result.Eligibility.IsEligible {
+			warnings = append(warnings, fmt.Sprintf("%s is %s for a TFSA - contributions to it cannot be held tax-free",
+				holdings[i].Ticker, strings.ReplaceAll(holdings[i].Status, "_", " ")))
+		}
+	}
+	return holdings, ruleVersion, warnings
+}
+
+// validatePlan limits the planner to ZA TFSAs with a horizon, unique
+// holdings weighted to 100% and no contributions in future tax years
+func validatePlan(req dto.TFSAPlanRequest, start time.Time) error {
+	profile := req.InvestorProfile
+	if profile.Country != "ZA" || profile.AccountType != "tfsa" {
+		return fmt.Errorf("the contribution planner supports country 'ZA' with account type 'tfsa' only")
+	}
+	if profile.TimeHorizonYears == 0 {
+		return fmt.Errorf("investorProfile.timeHorizonYears is required to project the plan")
+	}
+
+	seen := make(map[string]bool, len(req.Holdings))
+	sum := 0.0
+	for _, holding := range req.Holdings {
+		ticker := strings.ToUpper(strings.TrimSpace(holding.Ticker))
+		if seen[ticker] {
+			return fmt.Errorf("duplicate holding: %s", holding.Ticker)
+		}
+		seen[ticker] = true
+		sum += holding.Weight
+	}
+	if sum < 99 || sum > 101 {
+		return fmt.Errorf("holding weights must sum to 100, got %.2f", sum)
+	}
+
+	current := tfsa.TaxYear(start)
+	for _, contribution := range req.PriorContributions {
+		if contribution.TaxYear > current {
+			return fmt.Errorf("prior contribution for tax year %d is after the current tax year %d", contribution.TaxYear, current)
+		}
+	}
+	return nil
+}
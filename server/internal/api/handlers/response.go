@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"upstonk/internal/api/dto"
+)
+
+// respondJSON writes data as a JSON response with the given status
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// respondError writes the standard error body shared by every handler
+func respondError(w http.ResponseWriter, requestID string, status int, code, message, details string) {
+	errorResp := dto.ErrorResponse{
+		Error:     code,
+		Message:   message,
+		Code:      code,
+		RequestID: requestID,
+	}
+
+	if details != "" {
+		errorResp.Details = map[string]string{"details": details}
+	}
+
+	respondJSON(w, status, errorResp)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"upstonk/internal/service/search"
+)
+
+type UniverseHandler struct {
+	universe *search.Universe
+	admin    AdminAuth
+}
+
+func NewUniverseHandler(universe *search.Universe, admin AdminAuth) *UniverseHandler {
+	return &UniverseHandler{
+		universe: universe,
+		admin:    admin,
+	}
+}
+
+// HandleReload re-reads the ticker universe file: POST /api/v1/admin/universe/reload
+func (h *UniverseHandler) HandleReload(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New().String()
+
+	if !h.admin.authorize(w, r, requestID) {
+		return
+	}
+
+	if err := h.universe.Reload(); err != nil {
+		respondError(w, requestID, http.StatusUnprocessableEntity, "RELOAD_FAILED",
+			"Universe file could not be loaded; the previous version is still active", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.universe.Info())
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,7 +15,19 @@ type Config struct {
 	Environment     string
 	JSEAPI          JSEAPIConfig
 	AlphaVantageKey string
+	AlphaVantage    AlphaVantageConfig
+	AdminAPIKey     string
+	AdminOpen       bool // Admin endpoints accept unauthenticated requests when no key is set (development only)
 	Cache           CacheConfig
+	Catalog         CatalogConfig
+	Universe        UniverseConfig
+	Rules           RulesConfig
+	Platforms       PlatformsConfig
+	TFSA            TFSAConfig
+	Costs           CostsConfig
+	HTTPFixtures    HTTPFixturesConfig
+	Merge           MergeConfig
+	Providers       ProvidersConfig
 	Logging         LoggingConfig
 }
 
@@ -23,10 +37,74 @@ type JSEAPIConfig struct {
 	Timeout int
 }
 
+// AlphaVantageConfig sets the request budget for the Alpha Vantage API
+type AlphaVantageConfig struct {
+	DailyLimit  int    // Requests per UTC day
+	MinuteLimit int    // Requests per rolling minute
+	QuotaPath   string // File persisting usage across restarts
+}
+
 type CacheConfig struct {
-	Enabled    bool
-	TTLSeconds int
-	MaxSize    int
+	Enabled             bool
+	TTLSeconds          int
+	MaxStalenessSeconds int // Expired entries are served stale while refreshing, up to this age
+	MaxSize             int
+}
+
+type CatalogConfig struct {
+	Enabled                bool
+	Path                   string
+	RefreshIntervalSeconds int
+}
+
+// RulesConfig locates declarative eligibility rule files
+type RulesConfig struct {
+	Dir    string // Every *.json file here is loaded as a rule set
+	Policy string // How several applicable rule sets combine: strictest, any_fail or first_match
+}
+
+// PlatformsConfig locates the instrument lists investment platforms offer per account type
+type PlatformsConfig struct {
+	Dir string // Every *.json file here is loaded as a platform overlay
+}
+
+// TFSAConfig locates the versioned TFSA contribution caps
+type TFSAConfig struct {
+	CapsPath string // Built-in caps are used when the file cannot be loaded
+}
+
+// CostsConfig locates the brokerage schedules holding costs are estimated with
+type CostsConfig struct {
+	SchedulesPath string // A single built-in schedule is used when the file cannot be loaded
+}
+
+// UniverseConfig locates the ticker registry the live providers fetch from
+type UniverseConfig struct {
+	Path                  string
+	ReloadIntervalSeconds int // How often to check the file for changes (0 disables)
+}
+
+// HTTPFixturesConfig controls record/replay of outbound provider traffic.
+// Mode is "live" (default), "record" or "replay".
+type HTTPFixturesConfig struct {
+	Mode string
+	Dir  string
+}
+
+// MergeConfig controls how provider records for the same ETF are combined
+type MergeConfig struct {
+	SourcePrecedence  []string // DataSource.Reliability values, most trusted first
+	ConflictTolerance float64  // Relative difference that counts as a numeric conflict
+}
+
+// ProvidersConfig controls per-provider timeouts, circuit breakers and fan-out
+type ProvidersConfig struct {
+	TimeoutSeconds   int // Per-call limit for each upstream provider
+	FailureThreshold int // Consecutive failures before a provider is skipped
+	CooldownSeconds  int // How long a failing provider is skipped
+	FetchWorkers     int // Tickers fetched concurrently per live search
+	FetchPerHost     int // Concurrent requests to any one upstream host
+	FetchMaxRetries  int // Retries on 429/5xx responses
 }
 
 type LoggingConfig struct {
@@ -45,15 +123,61 @@ func Load() (*Config, error) {
 		ServerAddress:   getEnv("SERVER_ADDRESS", ":8080"),
 		Environment:     getEnv("ENVIRONMENT", "development"),
 		AlphaVantageKey: getEnv("ALPHA_VANTAGE_API_KEY", "demo"),
+		AlphaVantage: AlphaVantageConfig{
+			DailyLimit:  getEnvInt("ALPHA_VANTAGE_DAILY_LIMIT", 25),
+			MinuteLimit: getEnvInt("ALPHA_VANTAGE_MINUTE_LIMIT", 5),
+			QuotaPath:   getEnv("ALPHA_VANTAGE_QUOTA_PATH", "data/alphavantage_quota.json"),
+		},
+		AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
+		AdminOpen:   getEnv("ADMIN_OPEN", "false") == "true",
 		JSEAPI: JSEAPIConfig{
 			BaseURL: getEnv("JSE_API_BASE_URL", "https://api.jse.co.za/v1"),
 			APIKey:  getEnv("JSE_API_KEY", ""),
 			Timeout: 10,
 		},
 		Cache: CacheConfig{
-			Enabled:    getEnv("CACHE_ENABLED", "true") == "true",
-			TTLSeconds: 3600,
-			MaxSize:    1000,
+			Enabled:             getEnv("CACHE_ENABLED", "true") == "true",
+			TTLSeconds:          getEnvInt("CACHE_TTL_SECONDS", 3600),
+			MaxStalenessSeconds: getEnvInt("CACHE_MAX_STALENESS_SECONDS", 21600),
+			MaxSize:             getEnvInt("CACHE_MAX_SIZE", 1000),
+		},
+		Catalog: CatalogConfig{
+			Enabled:                getEnv("CATALOG_ENABLED", "true") == "true",
+			Path:                   getEnv("CATALOG_PATH", "data/catalog.json"),
+			RefreshIntervalSeconds: getEnvInt("CATALOG_REFRESH_INTERVAL_SECONDS", 21600),
+		},
+		Universe: UniverseConfig{
+			Path:                  getEnv("UNIVERSE_PATH", "data/universe.json"),
+			ReloadIntervalSeconds: getEnvInt("UNIVERSE_RELOAD_INTERVAL_SECONDS", 60),
+		},
+		Rules: RulesConfig{
+			Dir:    getEnv("RULES_DIR", "data/rules"),
+			Policy: getEnv("RULES_POLICY", "strictest"),
+		},
+		Platforms: PlatformsConfig{
+			Dir: getEnv("PLATFORMS_DIR", "data/platforms"),
+		},
+		TFSA: TFSAConfig{
+			CapsPath: getEnv("TFSA_CAPS_PATH", "data/tfsa_caps.json"),
+		},
+		Costs: CostsConfig{
+			SchedulesPath: getEnv("BROKERAGE_SCHEDULES_PATH", "data/brokerage_schedules.json"),
+		},
+		HTTPFixtures: HTTPFixturesConfig{
+			Mode: getEnv("HTTP_FIXTURE_MODE", "live"),
+			Dir:  getEnv("HTTP_FIXTURE_DIR", "testdata/fixtures"),
+		},
+		Merge: MergeConfig{
+			SourcePrecedence:  strings.Split(getEnv("MERGE_SOURCE_PRECEDENCE", "Primary,Secondary,Tertiary"), ","),
+			ConflictTolerance: getEnvFloat("MERGE_CONFLICT_TOLERANCE", 0.05),
+		},
+		Providers: ProvidersConfig{
+			TimeoutSeconds:   getEnvInt("PROVIDER_TIMEOUT_SECONDS", 10),
+			FailureThreshold: getEnvInt("PROVIDER_FAILURE_THRESHOLD", 5),
+			CooldownSeconds:  getEnvInt("PROVIDER_COOLDOWN_SECONDS", 60),
+			FetchWorkers:     getEnvInt("LIVE_FETCH_WORKERS", 8),
+			FetchPerHost:     getEnvInt("LIVE_FETCH_PER_HOST", 4),
+			FetchMaxRetries:  getEnvInt("LIVE_FETCH_MAX_RETRIES", 2),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -72,6 +196,16 @@ func (c *Config) Validate() error {
 	if c.ServerAddress == "" {
 		return fmt.Errorf("SERVER_ADDRESS is required")
 	}
+	switch c.HTTPFixtures.Mode {
+	case "live", "record", "replay":
+	default:
+		return fmt.Errorf("HTTP_FIXTURE_MODE must be live, record or replay, got %q", c.HTTPFixtures.Mode)
+	}
+	switch c.Rules.Policy {
+	case "strictest", "any_fail", "first_match":
+	default:
+		return fmt.Errorf("RULES_POLICY must be strictest, any_fail or first_match, got %q", c.Rules.Policy)
+	}
 	return nil
 }
 
@@ -81,3 +215,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
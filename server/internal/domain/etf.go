@@ -33,7 +33,8 @@ type ETF struct {
 	TrackingDifference float64 `json:"trackingDifference,omitempty"` // Annualized (%)
 	AUM                float64 `json:"aum"`                          // Assets Under Management (base currency)
 	Currency           string  `json:"currency"`
-	DividendTreatment  string  `json:"dividendTreatment"` // "Distributing", "Accumulating"
+	DividendTreatment  string  `json:"dividendTreatment"`       // "Distributing", "Accumulating"
+	DividendYield      float64 `json:"dividendYield,omitempty"` // Trailing 12-month (%)
 
 	// Liquidity
 	AverageDailyVolume float64 `json:"averageDailyVolume"`
@@ -44,8 +45,10 @@ type ETF struct {
 	Provider      string    `json:"provider"` // e.g., "Satrix", "CoreShares", "Vanguard"
 
 	// Source attribution
-	DataSources []DataSource `json:"dataSources"`
-	LastUpdated time.Time    `json:"lastUpdated"`
+	DataSources     []DataSource          `json:"dataSources"`
+	FieldProvenance map[string]DataSource `json:"fieldProvenance,omitempty"` // Field name -> source that supplied it
+	DataConflicts   []DataConflict        `json:"dataConflicts,omitempty"`
+	LastUpdated     time.Time             `json:"lastUpdated"`
 }
 
 // AssetExposure describes what the ETF invests in
@@ -87,6 +90,20 @@ type DataSource struct {
 	Reliability string    `json:"reliability"` // "Primary", "Secondary", "Tertiary"
 }
 
+// DataConflict records sources disagreeing on a field beyond tolerance
+type DataConflict struct {
+	Field    string         `json:"field"`
+	Values   []SourcedValue `json:"values"`
+	Selected string         `json:"selected"` // Provider whose value was kept
+}
+
+// SourcedValue is one source's reported value for a field
+type SourcedValue struct {
+	Provider    string `json:"provider"`
+	Reliability string `json:"reliability"`
+	Value       string `json:"value"`
+}
+
 // EligibilityResult captures eligibility determination
 type EligibilityResult struct {
 	IsEligible   bool                  `json:"isEligible"`
@@ -99,6 +116,20 @@ type EligibilityResult struct {
 	Evidence     []EligibilityEvidence `json:"evidence"`
 	RuleVersion  string                `json:"ruleVersion"` // e.g., "tfsa_za_v1.2"
 	EvaluatedAt  time.Time             `json:"evaluatedAt"`
+	AsOf         time.Time             `json:"asOf"` // Date whose rule versions were applied
+	RuleResults  []RuleOutcome         `json:"ruleResults,omitempty"`
+
+	// EvidenceCompleteness is the share of criteria (0-100) backed by evidence
+	// with a definite result and a cited data source
+	EvidenceCompleteness float64 `json:"evidenceCompleteness"`
+}
+
+// RuleOutcome is one rule set's verdict within a composed eligibility result
+type RuleOutcome struct {
+	Rule       string            `json:"rule"`
+	Version    string            `json:"version"`
+	Status     EligibilityStatus `json:"status"`
+	Confidence ConfidenceLevel   `json:"confidence"`
 }
 
 type EligibilityStatus string
@@ -108,6 +139,7 @@ const (
 	StatusIneligible  EligibilityStatus = "ineligible"
 	StatusUnknown     EligibilityStatus = "unknown"
 	StatusConditional EligibilityStatus = "conditional" // Eligible with warnings
+	StatusNotOffered  EligibilityStatus = "not_offered" // Eligible, but not offered on the investor's platform
 )
 
 type ConfidenceLevel string
@@ -119,6 +151,15 @@ const (
 	ConfidenceNone   ConfidenceLevel = "none"   // Insufficient data
 )
 
+// ConfidenceRank orders confidence levels from none (0) to high (3); a level
+// missing from it is not a valid confidence
+var ConfidenceRank = map[ConfidenceLevel]int{
+	ConfidenceNone:   0,
+	ConfidenceLow:    1,
+	ConfidenceMedium: 2,
+	ConfidenceHigh:   3,
+}
+
 // EligibilityEvidence provides audit trail
 type EligibilityEvidence struct {
 	Criterion  string     `json:"criterion"`
@@ -126,6 +167,7 @@ type EligibilityEvidence struct {
 	Actual     string     `json:"actual"`
 	Result     string     `json:"result"` // "pass", "fail", "unknown"
 	DataSource DataSource `json:"dataSource,omitempty"`
+	Rule       string     `json:"rule,omitempty"` // Rule set that recorded this criterion
 }
 
 // RankingScore represents weighted scoring
@@ -136,10 +178,59 @@ type RankingScore struct {
 	Explanation     string             `json:"explanation"`
 }
 
+// TaxDrag estimates the dividend tax an investor loses on an ETF each year
+type TaxDrag struct {
+	FundDomicile      string          `json:"fundDomicile"`
+	DividendYield     float64         `json:"dividendYield"`     // % per year used for the estimate
+	YieldAssumed      bool            `json:"yieldAssumed"`      // Not reported; typical yield for the asset class
+	FundLevelRate     float64         `json:"fundLevelRate"`     // % of dividends withheld before they reach the fund
+	InvestorLevelRate float64         `json:"investorLevelRate"` // % of distributions withheld or taxed on the way to the investor
+	EffectiveRate     float64         `json:"effectiveRate"`     // Combined % of dividends lost
+	AnnualDrag        float64         `json:"annualDrag"`        // % of fund value lost per year
+	Confidence        ConfidenceLevel `json:"confidence"`
+	Notes             []string        `json:"notes,omitempty"`
+	TableVersion      string          `json:"tableVersion"`
+}
+
+// CostBreakdown estimates the all-in cost of holding an ETF. Rates are % of
+// the amount invested per year, with entry and exit costs spread over the
+// holding period.
+type CostBreakdown struct {
+	Amount          float64  `json:"amount"` // Invested, in Currency
+	Currency        string   `json:"currency"`
+	HoldingYears    int      `json:"holdingYears"`
+	TER             float64  `json:"ter"`
+	TERAssumed      bool     `json:"terAssumed"`
+	Spread          float64  `json:"spread"` // Bid-ask spread, half on entry and half on exit
+	SpreadAssumed   bool     `json:"spreadAssumed"`
+	FX              float64  `json:"fx"`            // Currency conversion on entry and exit
+	Brokerage       float64  `json:"brokerage"`     // Commission on entry and exit
+	PlatformFee     float64  `json:"platformFee"`   // Platform and account fees
+	EffectiveCost   float64  `json:"effectiveCost"` // All of the above
+	AnnualCost      float64  `json:"annualCost"`    // EffectiveCost of Amount, in Currency
+	TotalCost       float64  `json:"totalCost"`     // Over HoldingYears
+	Schedule        string   `json:"schedule"`      // Brokerage schedule applied
+	ScheduleVersion string   `json:"scheduleVersion"`
+	Notes           []string `json:"notes,omitempty"`
+}
+
 // DiscoveredETF combines ETF data with eligibility and ranking
 type DiscoveredETF struct {
 	ETF         ETF               `json:"etf"`
 	Eligibility EligibilityResult `json:"eligibility"`
 	Ranking     RankingScore      `json:"ranking"`
 	MatchScore  float64           `json:"matchScore"` // How well it matches requested exposure (0-100)
+	TaxDrag     *TaxDrag          `json:"taxDrag,omitempty"`
+	Costs       *CostBreakdown    `json:"costs,omitempty"`
+}
+
+// SitusExposure flags estate tax a foreign jurisdiction can levy on an
+// investor's holding because of where the fund is domiciled
+type SitusExposure struct {
+	Jurisdiction string  `json:"jurisdiction"` // Country levying the tax, e.g. "US"
+	FundDomicile string  `json:"fundDomicile"`
+	ExemptionUSD float64 `json:"exemptionUsd"` // Holdings above this are exposed
+	TopRate      float64 `json:"topRate"`      // %
+	TreatyRelief bool    `json:"treatyRelief"` // An estate tax treaty may raise the exemption
+	Reason       string  `json:"reason"`
 }
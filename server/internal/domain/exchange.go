@@ -0,0 +1,25 @@
+package domain
+
+import "strings"
+
+// exchangeCountries maps exchange codes and names, as reported by data
+// providers and analyst lists, to the listing country. Yahoo Finance reports
+// its own codes (NYQ, NAS, JNB, ...) alongside the common ones.
+var exchangeCountries = map[string]string{
+	"NYSE": "US", "NYQ": "US", "NYSEARCA": "US", "NYSE ARCA": "US", "PCX": "US",
+	"AMEX": "US", "ASE": "US", "NYSEAMERICAN": "US",
+	"NASDAQ": "US", "NAS": "US", "NMS": "US", "NGM": "US", "NCM": "US",
+	"BATS": "US", "BTS": "US", "CBOE": "US", "BZX": "US",
+	"LSE": "GB", "LON": "GB", "LONDON STOCK EXCHANGE": "GB",
+	"JSE": "ZA", "JNB": "ZA", "JOHANNESBURG STOCK EXCHANGE": "ZA",
+	"FRA": "DE", "ETR": "DE", "XETR": "DE", "XETRA": "DE", "GER": "DE",
+	"TSE": "JP", "TYO": "JP",
+	"ASX": "AU",
+	"TSX": "CA", "TOR": "CA",
+}
+
+// ExchangeCountry returns the ISO country of an exchange code or name, or ""
+// when the exchange is not known
+func ExchangeCountry(exchange string) string {
+	return exchangeCountries[strings.ToUpper(strings.TrimSpace(exchange))]
+}
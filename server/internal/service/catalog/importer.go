@@ -0,0 +1,582 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// Format identifies the file format of an import
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ImportOptions controls how imported rows are tagged and stored
+type ImportOptions struct {
+	SourceName  string // Recorded as DataSource.Provider, e.g. "Analyst spreadsheet"
+	Reliability string // "Primary", "Secondary" or "Tertiary"
+	DryRun      bool   // Validate only, do not write to the catalog
+}
+
+// ImportRecord is one row of an analyst-maintained ETF list
+type ImportRecord struct {
+	Ticker            string             `json:"ticker"`
+	Name              string             `json:"name"`
+	ISIN              string             `json:"isin"`
+	Exchange          string             `json:"exchange"`
+	ExchangeCountry   string             `json:"exchangeCountry"`
+	Domicile          string             `json:"domicile"`
+	LegalStructure    string             `json:"legalStructure"`
+	ReplicationMethod string             `json:"replicationMethod"`
+	IsLeveraged       bool               `json:"isLeveraged"`
+	IsInverse         bool               `json:"isInverse"`
+	AssetClass        string             `json:"assetClass"`
+	TrackingIndex     string             `json:"trackingIndex"`
+	Regions           map[string]float64 `json:"regions"`
+	Countries         map[string]float64 `json:"countries"`
+	Sectors           map[string]float64 `json:"sectors"`
+	TER               *float64           `json:"ter"`
+	AUM               float64            `json:"aum"`
+	Currency          string             `json:"currency"`
+	DividendTreatment string             `json:"dividendTreatment"`
+	DividendYield     float64            `json:"dividendYield"`
+	BidAskSpread      float64            `json:"bidAskSpread"`
+	InceptionDate     string             `json:"inceptionDate"`
+	Provider          string             `json:"provider"`
+	SourceURL         string             `json:"sourceUrl"`
+}
+
+// RowError describes why a single row was rejected
+type RowError struct {
+	Row     int    `json:"row"` // 1-based data row (CSV header excluded)
+	Ticker  string `json:"ticker,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport summarises an import run
+type ImportReport struct {
+	Source   string     `json:"source"`
+	Format   Format     `json:"format"`
+	DryRun   bool       `json:"dryRun"`
+	RowsRead int        `json:"rowsRead"`
+	Imported int        `json:"imported"`
+	Rejected int        `json:"rejected"`
+	Errors   []RowError `json:"errors,omitempty"`
+}
+
+var validReliability = map[string]bool{
+	"Primary":   true,
+	"Secondary": true,
+	"Tertiary":  true,
+}
+
+// csvColumns maps accepted spreadsheet headings onto ImportRecord fields
+var csvColumns = map[string]string{
+	"ticker":             "ticker",
+	"code":               "ticker",
+	"name":               "name",
+	"fund name":          "name",
+	"isin":               "isin",
+	"exchange":           "exchange",
+	"exchange country":   "exchangeCountry",
+	"exchangecountry":    "exchangeCountry",
+	"domicile":           "domicile",
+	"legal structure":    "legalStructure",
+	"legalstructure":     "legalStructure",
+	"replication":        "replicationMethod",
+	"replication method": "replicationMethod",
+	"replicationmethod":  "replicationMethod",
+	"leveraged":          "isLeveraged",
+	"isleveraged":        "isLeveraged",
+	"inverse":            "isInverse",
+	"isinverse":          "isInverse",
+	"asset class":        "assetClass",
+	"assetclass":         "assetClass",
+	"index":              "trackingIndex",
+	"tracking index":     "trackingIndex",
+	"trackingindex":      "trackingIndex",
+	"regions":            "regions",
+	"countries":          "countries",
+	"sectors":            "sectors",
+	"ter":                "ter",
+	"ter %":              "ter",
+	"aum":                "aum",
+	"currency":           "currency",
+	"dividend treatment": "dividendTreatment",
+	"dividendtreatment":  "dividendTreatment",
+	"distribution":       "dividendTreatment",
+	"dividend yield":     "dividendYield",
+	"dividendyield":      "dividendYield",
+	"yield":              "dividendYield",
+	"bid ask spread":     "bidAskSpread",
+	"bidaskspread":       "bidAskSpread",
+	"spread":             "bidAskSpread",
+	"inception date":     "inceptionDate",
+	"inceptiondate":      "inceptionDate",
+	"provider":           "provider",
+	"issuer":             "provider",
+	"source url":         "sourceUrl",
+	"sourceurl":          "sourceUrl",
+	"factsheet":          "sourceUrl",
+}
+
+// Importer loads analyst-maintained ETF lists into the catalog
+type Importer struct {
+	store *Store
+}
+
+func NewImporter(store *Store) *Importer {
+	return &Importer{store: store}
+}
+
+// ImportFile imports a CSV or JSON file, detecting the format from its extension
+func (i *Importer) ImportFile(path string, opts ImportOptions) (ImportReport, error) {
+	format, err := FormatFromName(path)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if opts.SourceName == "" {
+		opts.SourceName = filepath.Base(path)
+	}
+
+	return i.Import(file, format, opts)
+}
+
+// Import validates every row, upserts the valid ones and persists the catalog.
+// A non-nil error means the input could not be parsed at all; row-level
+// problems are reported in ImportReport.Errors.
+func (i *Importer) Import(r io.Reader, format Format, opts ImportOptions) (ImportReport, error) {
+	if opts.Reliability == "" {
+		opts.Reliability = "Secondary"
+	}
+	if !validReliability[opts.Reliability] {
+		return ImportReport{}, fmt.Errorf("invalid reliability %q: must be Primary, Secondary or Tertiary", opts.Reliability)
+	}
+	if opts.SourceName == "" {
+		opts.SourceName = "Manual import"
+	}
+
+	var records []ImportRecord
+	var parseErrors []RowError
+	var err error
+
+	switch format {
+	case FormatCSV:
+		records, parseErrors, err = parseCSV(r)
+	case FormatJSON:
+		records, err = parseJSON(r)
+	default:
+		err = fmt.Errorf("unsupported import format %q", format)
+	}
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	report := ImportReport{
+		Source:   opts.SourceName,
+		Format:   format,
+		DryRun:   opts.DryRun,
+		RowsRead: len(records),
+		Errors:   parseErrors,
+	}
+
+	rejectedRows := make(map[int]bool)
+	for _, rowErr := range parseErrors {
+		rejectedRows[rowErr.Row] = true
+	}
+
+	source := domain.DataSource{
+		Type:        "Manual",
+		Provider:    opts.SourceName,
+		AccessDate:  time.Now(),
+		Reliability: opts.Reliability,
+	}
+
+	valid := make([]domain.ETF, 0, len(records))
+	for idx, record := range records {
+		row := idx + 1
+		if rejectedRows[row] {
+			continue
+		}
+
+		etf, rowErrors := record.toETF(row, source)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			rejectedRows[row] = true
+			continue
+		}
+		valid = append(valid, etf)
+	}
+
+	report.Rejected = len(rejectedRows)
+	if opts.DryRun {
+		report.Imported = len(valid)
+		return report, nil
+	}
+
+	report.Imported = i.store.Upsert(valid...)
+	if err := i.store.Save(); err != nil {
+		return report, fmt.Errorf("persist catalog: %w", err)
+	}
+
+	return report, nil
+}
+
+// FormatFromName infers the import format from a file name or content type
+func FormatFromName(name string) (Format, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".csv"), strings.Contains(lower, "text/csv"):
+		return FormatCSV, nil
+	case strings.HasSuffix(lower, ".json"), strings.Contains(lower, "application/json"):
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("cannot determine import format for %q (expected .csv or .json)", name)
+}
+
+func parseJSON(r io.Reader) ([]ImportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read JSON: %w", err)
+	}
+
+	// Accept either a bare array or an object wrapping it: {"etfs": [...]}
+	var records []ImportRecord
+	if err := json.Unmarshal(data, &records); err == nil {
+		return records, nil
+	}
+
+	var wrapped struct {
+		ETFs []ImportRecord `json:"etfs"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+	return wrapped.ETFs, nil
+}
+
+func parseCSV(r io.Reader) ([]ImportRecord, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	for idx, heading := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(heading, "\ufeff")))
+		key = strings.ReplaceAll(key, "_", " ")
+		columns[idx] = csvColumns[key]
+	}
+
+	records := make([]ImportRecord, 0)
+	rowErrors := make([]RowError, 0)
+
+	for row := 1; ; row++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read CSV row %d: %w", row, err)
+		}
+
+		var record ImportRecord
+		for idx, value := range values {
+			if idx >= len(columns) || columns[idx] == "" {
+				continue
+			}
+			if fieldErr := record.set(columns[idx], strings.TrimSpace(value)); fieldErr != nil {
+				rowErrors = append(rowErrors, RowError{
+					Row:     row,
+					Field:   columns[idx],
+					Message: fieldErr.Error(),
+				})
+			}
+		}
+
+		for idx := range rowErrors {
+			if rowErrors[idx].Row == row {
+				rowErrors[idx].Ticker = record.Ticker
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, rowErrors, nil
+}
+
+// set assigns a raw CSV cell to the named field
+func (rec *ImportRecord) set(field, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	var err error
+	switch field {
+	case "ticker":
+		rec.Ticker = value
+	case "name":
+		rec.Name = value
+	case "isin":
+		rec.ISIN = value
+	case "exchange":
+		rec.Exchange = value
+	case "exchangeCountry":
+		rec.ExchangeCountry = value
+	case "domicile":
+		rec.Domicile = value
+	case "legalStructure":
+		rec.LegalStructure = value
+	case "replicationMethod":
+		rec.ReplicationMethod = value
+	case "isLeveraged":
+		rec.IsLeveraged, err = parseBool(value)
+	case "isInverse":
+		rec.IsInverse, err = parseBool(value)
+	case "assetClass":
+		rec.AssetClass = value
+	case "trackingIndex":
+		rec.TrackingIndex = value
+	case "regions":
+		rec.Regions, err = parseWeights(value)
+	case "countries":
+		rec.Countries, err = parseWeights(value)
+	case "sectors":
+		rec.Sectors, err = parseWeights(value)
+	case "ter":
+		var ter float64
+		ter, err = parsePercent(value)
+		rec.TER = &ter
+	case "aum":
+		rec.AUM, err = strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	case "currency":
+		rec.Currency = value
+	case "dividendTreatment":
+		rec.DividendTreatment = value
+	case "dividendYield":
+		rec.DividendYield, err = parsePercent(value)
+	case "bidAskSpread":
+		rec.BidAskSpread, err = parsePercent(value)
+	case "inceptionDate":
+		rec.InceptionDate = value
+	case "provider":
+		rec.Provider = value
+	case "sourceUrl":
+		rec.SourceURL = value
+	}
+
+	return err
+}
+
+// toETF validates the record and converts it to a catalog entry
+func (rec ImportRecord) toETF(row int, source domain.DataSource) (domain.ETF, []RowError) {
+	errs := make([]RowError, 0)
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, RowError{
+			Row:     row,
+			Ticker:  rec.Ticker,
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	required := map[string]string{
+		"ticker":   rec.Ticker,
+		"name":     rec.Name,
+		"isin":     rec.ISIN,
+		"exchange": rec.Exchange,
+		"domicile": rec.Domicile,
+	}
+	for _, field := range []string{"ticker", "name", "isin", "exchange", "domicile"} {
+		if strings.TrimSpace(required[field]) == "" {
+			fail(field, "%s is required", field)
+		}
+	}
+
+	if rec.TER == nil {
+		fail("ter", "ter is required")
+	} else if *rec.TER < 0 || *rec.TER > 5 {
+		fail("ter", "ter %.4f is outside the expected range 0-5%%", *rec.TER)
+	}
+	if rec.DividendYield < 0 || rec.DividendYield > 25 {
+		fail("dividendYield", "dividend yield %.4f is outside the expected range 0-25%%", rec.DividendYield)
+	}
+
+	isin := strings.ToUpper(strings.TrimSpace(rec.ISIN))
+	if isin != "" && !validISIN(isin) {
+		fail("isin", "invalid ISIN %q", rec.ISIN)
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(rec.Currency))
+	if currency != "" && len(currency) != 3 {
+		fail("currency", "currency must be a 3-letter ISO 4217 code, got %q", rec.Currency)
+	}
+
+	var inception time.Time
+	if rec.InceptionDate != "" {
+		parsed, err := time.Parse("2006-01-02", rec.InceptionDate)
+		if err != nil {
+			fail("inceptionDate", "inception date must be YYYY-MM-DD, got %q", rec.InceptionDate)
+		}
+		inception = parsed
+	}
+
+	if len(errs) > 0 {
+		return domain.ETF{}, errs
+	}
+
+	replication := strings.ToLower(rec.ReplicationMethod)
+	isSynthetic := strings.Contains(replication, "synthetic") || strings.Contains(replication, "swap")
+
+	source.URL = rec.SourceURL
+
+	// Listing country drives ZA-investor scoping; derive it when the list omits it
+	exchangeCountry := strings.ToUpper(strings.TrimSpace(rec.ExchangeCountry))
+	if exchangeCountry == "" {
+		exchangeCountry = domain.ExchangeCountry(rec.Exchange)
+	}
+
+	etf := domain.ETF{
+		Ticker:            strings.ToUpper(strings.TrimSpace(rec.Ticker)),
+		Name:              rec.Name,
+		ISIN:              isin,
+		Exchange:          strings.ToUpper(rec.Exchange),
+		ExchangeCountry:   exchangeCountry,
+		Domicile:          strings.ToUpper(rec.Domicile),
+		LegalStructure:    rec.LegalStructure,
+		IsPhysical:        replication != "" && !isSynthetic,
+		IsSynthetic:       isSynthetic,
+		IsLeveraged:       rec.IsLeveraged,
+		IsInverse:         rec.IsInverse,
+		ReplicationMethod: rec.ReplicationMethod,
+		AssetClass:        rec.AssetClass,
+		TrackingIndex:     rec.TrackingIndex,
+		GeographicExposure: domain.GeographicExposure{
+			Regions:   rec.Regions,
+			Countries: rec.Countries,
+		},
+		TER:               *rec.TER,
+		AUM:               rec.AUM,
+		Currency:          currency,
+		DividendTreatment: rec.DividendTreatment,
+		DividendYield:     rec.DividendYield,
+		BidAskSpread:      rec.BidAskSpread,
+		InceptionDate:     inception,
+		Provider:          rec.Provider,
+		DataSources:       []domain.DataSource{source},
+		LastUpdated:       source.AccessDate,
+	}
+
+	for sector, weight := range rec.Sectors {
+		etf.SectorExposure = append(etf.SectorExposure, domain.SectorAllocation{
+			Sector:     sector,
+			Percentage: weight,
+		})
+	}
+
+	return etf, nil
+}
+
+// parseWeights parses "usa:60;europe:40" style cells
+func parseWeights(value string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '|' }) {
+		name, weight, found := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !found {
+			weights[name] = 0
+			continue
+		}
+		parsed, err := parsePercent(weight)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %q: %w", name, err)
+		}
+		weights[name] = parsed
+	}
+	return weights, nil
+}
+
+func parsePercent(value string) (float64, error) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "true", "1":
+		return true, nil
+	case "no", "n", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected yes/no, got %q", value)
+}
+
+// validISIN checks the ISO 6166 format and check digit
+func validISIN(isin string) bool {
+	if len(isin) != 12 {
+		return false
+	}
+	for idx, r := range isin {
+		switch {
+		case idx < 2 && (r < 'A' || r > 'Z'):
+			return false
+		case idx == 11 && (r < '0' || r > '9'):
+			return false
+		case (r < 'A' || r > 'Z') && (r < '0' || r > '9'):
+			return false
+		}
+	}
+
+	// Expand letters to numbers (A=10 ... Z=35), then apply the Luhn algorithm
+	var digits strings.Builder
+	for _, r := range isin {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+
+	expanded := digits.String()
+	sum := 0
+	double := false
+	for idx := len(expanded) - 1; idx >= 0; idx-- {
+		d := int(expanded[idx] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"upstonk/internal/domain"
+	"upstonk/internal/service/search"
+)
+
+// Provider answers search queries from the local catalog instead of the network
+type Provider struct {
+	store    *Store
+	fallback search.Provider
+}
+
+// NewProvider creates a catalog-backed search provider. The fallback, if not
+// nil, is only consulted while the catalog is still empty (cold start); its
+// results are upserted so subsequent queries are served locally.
+func NewProvider(store *Store, fallback search.Provider) *Provider {
+	return &Provider{
+		store:    store,
+		fallback: fallback,
+	}
+}
+
+// Search implements the search.Provider interface
+func (p *Provider) Search(ctx context.Context, criteria search.Criteria) ([]domain.ETF, error) {
+	if p.store.Len() == 0 && p.fallback != nil {
+		log.Printf("Catalog is empty, falling back to live providers")
+
+		etfs, err := p.fallback.Search(ctx, criteria)
+		if err != nil {
+			return nil, err
+		}
+
+		p.store.Upsert(etfs...)
+		if err := p.store.Save(); err != nil {
+			log.Printf("Failed to persist catalog: %v", err)
+		}
+		return etfs, nil
+	}
+
+	start := time.Now()
+	results := p.store.Query(criteria)
+	log.Printf("Catalog query matched %d ETFs", len(results))
+	search.TraceFrom(ctx).RecordSource(search.SourceStatus{
+		Name:     "catalog",
+		Status:   search.SourceAnswered,
+		Results:  len(results),
+		Duration: time.Since(start),
+	})
+
+	if missing := p.missing(criteria.Identifiers); len(missing) > 0 && p.fallback != nil {
+		results = append(results, p.lookupLive(ctx, criteria, missing)...)
+	}
+	return results, nil
+}
+
+// missing returns the identifiers the catalog has no record for
+func (p *Provider) missing(identifiers []string) []string {
+	var missing []string
+	for _, identifier := range identifiers {
+		if _, ok := p.store.Get(identifier); !ok {
+			missing = append(missing, identifier)
+		}
+	}
+	return missing
+}
+
+// lookupLive fetches instruments the catalog does not hold yet and keeps them
+func (p *Provider) lookupLive(ctx context.Context, criteria search.Criteria, identifiers []string) []domain.ETF {
+	criteria.Identifiers = identifiers
+	etfs, err := p.fallback.Search(ctx, criteria)
+	if err != nil {
+		log.Printf("Live lookup of %v failed: %v", identifiers, err)
+		return nil
+	}
+	if len(etfs) > 0 {
+		p.store.Upsert(etfs...)
+		if err := p.store.Save(); err != nil {
+			log.Printf("Failed to persist catalog: %v", err)
+		}
+	}
+	return etfs
+}
@@ -0,0 +1,108 @@
+package catalog
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"upstonk/internal/service/search"
+)
+
+// Refresher periodically pulls data from live providers and upserts it into the catalog
+type Refresher struct {
+	store    *Store
+	upstream search.Provider
+	seeds    []search.Criteria
+	interval time.Duration
+}
+
+func NewRefresher(store *Store, upstream search.Provider, interval time.Duration, seeds ...search.Criteria) *Refresher {
+	if len(seeds) == 0 {
+		seeds = DefaultSeeds()
+	}
+
+	return &Refresher{
+		store:    store,
+		upstream: upstream,
+		seeds:    seeds,
+		interval: interval,
+	}
+}
+
+// DefaultSeeds returns the criteria used to sweep the live providers when no
+// explicit seed list is configured. Together they cover every market and
+// sector the live providers know how to resolve.
+func DefaultSeeds() []search.Criteria {
+	markets := []string{"usa", "emerging markets", "world", "europe", "china", "india", "south africa"}
+	sectors := []string{"technology", "healthcare", "financials", "energy"}
+	assetClasses := []string{"equity", "bond"}
+
+	seeds := make([]search.Criteria, 0)
+	for _, country := range []string{"ZA", "US"} {
+		for _, market := range markets {
+			seeds = append(seeds, search.Criteria{Country: country, Markets: []string{market}})
+		}
+		for _, sector := range sectors {
+			seeds = append(seeds, search.Criteria{Country: country, Sectors: []string{sector}})
+		}
+		for _, assetClass := range assetClasses {
+			seeds = append(seeds, search.Criteria{Country: country, AssetClasses: []string{assetClass}})
+		}
+	}
+
+	return seeds
+}
+
+// RefreshOnce sweeps every seed criteria and persists the catalog.
+// Returns the number of records written.
+func (r *Refresher) RefreshOnce(ctx context.Context) (int, error) {
+	written := 0
+
+	for _, seed := range r.seeds {
+		if ctx.Err() != nil {
+			break
+		}
+
+		etfs, err := r.upstream.Search(ctx, seed)
+		if err != nil {
+			log.Printf("Catalog refresh failed for %+v: %v", seed, err)
+			continue
+		}
+
+		written += r.store.Upsert(etfs...)
+	}
+
+	if err := r.store.Save(); err != nil {
+		return written, err
+	}
+
+	log.Printf("Catalog refresh complete: %d records written, %d in catalog", written, r.store.Len())
+	return written, ctx.Err()
+}
+
+// Start runs an immediate refresh and then one per interval until ctx is cancelled
+func (r *Refresher) Start(ctx context.Context) {
+	go func() {
+		if _, err := r.RefreshOnce(ctx); err != nil {
+			log.Printf("Catalog refresh error: %v", err)
+		}
+
+		if r.interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.RefreshOnce(ctx); err != nil {
+					log.Printf("Catalog refresh error: %v", err)
+				}
+			}
+		}
+	}()
+}
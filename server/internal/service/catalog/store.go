@@ -0,0 +1,485 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"upstonk/internal/domain"
+	"upstonk/internal/service/search"
+)
+
+// Store is a file-backed, in-process catalog of ETF records.
+// Records are kept in memory with secondary indexes on the fields that
+// search.Criteria can filter by, and persisted as a single JSON document.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	records map[string]domain.ETF
+
+	// Secondary indexes: normalized field value -> set of record keys
+	assetClasses map[string]keySet
+	regions      map[string]keySet
+	countries    map[string]keySet
+	sectors      map[string]keySet
+	holdings     map[string]keySet
+	exchanges    map[string]keySet
+	listings     map[string]keySet // Exchange country
+	isins        map[string]string
+
+	// Decides which source's value a field keeps when a record is refreshed
+	mergePolicy search.MergePolicy
+
+	// Records without any geographic data, matched leniently on market queries
+	unclassifiedGeography keySet
+}
+
+type keySet map[string]struct{}
+
+// catalogFile is the on-disk representation of the catalog
+type catalogFile struct {
+	Version   int          `json:"version"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	ETFs      []domain.ETF `json:"etfs"`
+}
+
+const catalogFileVersion = 1
+
+// Open loads the catalog at path, creating an empty catalog if the file does not exist
+func Open(path string) (*Store, error) {
+	s := newStore(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read catalog %s: %w", path, err)
+	}
+
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode catalog %s: %w", path, err)
+	}
+
+	for _, etf := range file.ETFs {
+		key := recordKey(etf)
+		if key == "" {
+			continue
+		}
+		s.records[key] = etf
+		s.indexRecord(key, etf)
+	}
+
+	return s, nil
+}
+
+// NewMemoryStore creates a catalog that is never persisted to disk
+func NewMemoryStore() *Store {
+	return newStore("")
+}
+
+func newStore(path string) *Store {
+	return &Store{
+		path:                  path,
+		records:               make(map[string]domain.ETF),
+		assetClasses:          make(map[string]keySet),
+		regions:               make(map[string]keySet),
+		countries:             make(map[string]keySet),
+		sectors:               make(map[string]keySet),
+		holdings:              make(map[string]keySet),
+		exchanges:             make(map[string]keySet),
+		listings:              make(map[string]keySet),
+		isins:                 make(map[string]string),
+		unclassifiedGeography: make(keySet),
+		mergePolicy:           search.DefaultMergePolicy(),
+	}
+}
+
+// SetMergePolicy replaces the source precedence used when refreshing records
+func (s *Store) SetMergePolicy(policy search.MergePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mergePolicy = policy
+}
+
+// Len returns the number of records in the catalog
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// Get returns the record for a ticker (with or without exchange suffix) or ISIN
+func (s *Store) Get(identifier string) (domain.ETF, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id := strings.ToUpper(strings.TrimSpace(identifier))
+	if etf, ok := s.records[id]; ok {
+		return etf, true
+	}
+	if key, ok := s.isins[id]; ok {
+		return s.records[key], true
+	}
+	// Records are keyed by base ticker: STXNDQ.JO is catalogued as STXNDQ
+	if base, suffix, ok := strings.Cut(id, "."); ok && suffix != "" {
+		if etf, ok := s.records[base]; ok {
+			return etf, true
+		}
+	}
+	return domain.ETF{}, false
+}
+
+// All returns every record, ordered by ticker
+func (s *Store) All() []domain.ETF {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.records))
+	for key := range s.records {
+		keys = append(keys, key)
+	}
+	return s.collect(keys)
+}
+
+// Upsert inserts or refreshes records. Each field keeps the value from the
+// most reliable source that supplied it (see search.MergePolicy), so fields
+// missing from an incoming record, or supplied by a less reliable source, keep
+// their catalogued values. Returns the number of records written.
+func (s *Store) Upsert(etfs ...domain.ETF) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	written := 0
+	for _, etf := range etfs {
+		key := recordKey(etf)
+		if key == "" {
+			continue
+		}
+
+		if existing, ok := s.records[key]; ok {
+			s.unindexRecord(key, existing)
+			etf = s.mergeRecord(existing, etf)
+		}
+
+		s.records[key] = etf
+		s.indexRecord(key, etf)
+		written++
+	}
+
+	return written
+}
+
+// Delete removes a record by ticker or ISIN
+func (s *Store) Delete(identifier string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToUpper(strings.TrimSpace(identifier))
+	if mapped, ok := s.isins[key]; ok {
+		key = mapped
+	}
+
+	existing, ok := s.records[key]
+	if !ok {
+		return false
+	}
+
+	s.unindexRecord(key, existing)
+	delete(s.records, key)
+	return true
+}
+
+// Query returns records matching the criteria. Values within a dimension are
+// OR-ed together, dimensions are AND-ed. Country limits results to local
+// listings where search.ListingCountry says so; Vehicles is not indexed.
+func (s *Store) Query(criteria search.Criteria) []domain.ETF {
+	if len(criteria.Identifiers) > 0 {
+		etfs := make([]domain.ETF, 0, len(criteria.Identifiers))
+		for _, identifier := range criteria.Identifiers {
+			if etf, ok := s.Get(identifier); ok {
+				etfs = append(etfs, etf)
+			}
+		}
+		return etfs
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var candidates keySet
+	narrow := func(matches keySet) {
+		if candidates == nil {
+			candidates = matches
+			return
+		}
+		candidates = intersect(candidates, matches)
+	}
+
+	if len(criteria.AssetClasses) > 0 {
+		narrow(matchIndex(s.assetClasses, criteria.AssetClasses, nil))
+	}
+	if len(criteria.Markets) > 0 {
+		markets := matchIndex(s.regions, criteria.Markets, marketAliases)
+		union(markets, matchIndex(s.countries, criteria.Markets, marketAliases))
+		union(markets, s.unclassifiedGeography)
+		narrow(markets)
+	}
+	if len(criteria.Sectors) > 0 {
+		narrow(matchIndex(s.sectors, criteria.Sectors, nil))
+	}
+	if len(criteria.Companies) > 0 {
+		narrow(matchIndex(s.holdings, criteria.Companies, nil))
+	}
+	if len(criteria.Exchanges) > 0 {
+		narrow(matchIndex(s.exchanges, criteria.Exchanges, nil))
+	}
+	if country := search.ListingCountry(criteria.Country); country != "" {
+		listed := make(keySet)
+		union(listed, s.listings[normalize(country)])
+		narrow(listed)
+	}
+
+	if candidates == nil {
+		candidates = make(keySet, len(s.records))
+		for key := range s.records {
+			candidates[key] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	return s.collect(keys)
+}
+
+// Save persists the catalog atomically (write to temp file, then rename)
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.records))
+	for key := range s.records {
+		keys = append(keys, key)
+	}
+	file := catalogFile{
+		Version:   catalogFileVersion,
+		UpdatedAt: time.Now().UTC(),
+		ETFs:      s.collect(keys),
+	}
+	s.mu.RUnlock()
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode catalog: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create catalog directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write catalog: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace catalog: %w", err)
+	}
+
+	return nil
+}
+
+// collect resolves keys to records sorted by key. Caller must hold the lock.
+func (s *Store) collect(keys []string) []domain.ETF {
+	sort.Strings(keys)
+	etfs := make([]domain.ETF, 0, len(keys))
+	for _, key := range keys {
+		etfs = append(etfs, s.records[key])
+	}
+	return etfs
+}
+
+func (s *Store) indexRecord(key string, etf domain.ETF) {
+	s.forEachIndexValue(etf, func(index map[string]keySet, value string) {
+		if index[value] == nil {
+			index[value] = make(keySet)
+		}
+		index[value][key] = struct{}{}
+	})
+
+	if !hasGeography(etf) {
+		s.unclassifiedGeography[key] = struct{}{}
+	}
+	if etf.ISIN != "" {
+		s.isins[strings.ToUpper(etf.ISIN)] = key
+	}
+}
+
+func (s *Store) unindexRecord(key string, etf domain.ETF) {
+	s.forEachIndexValue(etf, func(index map[string]keySet, value string) {
+		delete(index[value], key)
+		if len(index[value]) == 0 {
+			delete(index, value)
+		}
+	})
+
+	delete(s.unclassifiedGeography, key)
+	if etf.ISIN != "" {
+		delete(s.isins, strings.ToUpper(etf.ISIN))
+	}
+}
+
+func (s *Store) forEachIndexValue(etf domain.ETF, fn func(index map[string]keySet, value string)) {
+	add := func(index map[string]keySet, value string) {
+		if normalized := normalize(value); normalized != "" {
+			fn(index, normalized)
+		}
+	}
+
+	add(s.assetClasses, etf.AssetClass)
+	add(s.exchanges, etf.Exchange)
+	add(s.listings, listingCountry(etf))
+	for region := range etf.GeographicExposure.Regions {
+		add(s.regions, region)
+	}
+	for country := range etf.GeographicExposure.Countries {
+		add(s.countries, country)
+	}
+	for _, sector := range etf.SectorExposure {
+		add(s.sectors, sector.Sector)
+	}
+	for _, holding := range etf.TopHoldings {
+		add(s.holdings, holding.Name)
+		add(s.holdings, holding.Ticker)
+	}
+}
+
+// marketAliases maps common market names onto the region and country
+// spellings used by data providers
+var marketAliases = map[string][]string{
+	"usa":              {"us", "united states"},
+	"us":               {"usa", "united states"},
+	"united states":    {"usa", "us"},
+	"emerging":         {"emerging markets"},
+	"emerging markets": {"emerging"},
+	"world":            {"global"},
+	"global":           {"world"},
+	"international":    {"world", "global"},
+	"south africa":     {"za"},
+	"china":            {"cn"},
+	"india":            {"in"},
+	"united kingdom":   {"gb", "uk"},
+	"uk":               {"gb", "united kingdom"},
+}
+
+// matchIndex returns the union of all records whose indexed value matches any
+// of the requested terms. Terms of three or more characters also match values
+// that contain them (e.g. "equity" matches "us equity large cap").
+func matchIndex(index map[string]keySet, terms []string, aliases map[string][]string) keySet {
+	matches := make(keySet)
+
+	for _, term := range terms {
+		normalized := normalize(term)
+		if normalized == "" {
+			continue
+		}
+
+		candidates := append([]string{normalized}, aliases[normalized]...)
+		for _, candidate := range candidates {
+			if keys, ok := index[candidate]; ok {
+				union(matches, keys)
+			}
+			if len(candidate) < 3 {
+				continue
+			}
+			for value, keys := range index {
+				if strings.Contains(value, candidate) {
+					union(matches, keys)
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func union(dst, src keySet) {
+	for key := range src {
+		dst[key] = struct{}{}
+	}
+}
+
+func intersect(a, b keySet) keySet {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	result := make(keySet)
+	for key := range a {
+		if _, ok := b[key]; ok {
+			result[key] = struct{}{}
+		}
+	}
+	return result
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func recordKey(etf domain.ETF) string {
+	if etf.Ticker != "" {
+		return strings.ToUpper(strings.TrimSpace(etf.Ticker))
+	}
+	return strings.ToUpper(strings.TrimSpace(etf.ISIN))
+}
+
+// listingCountry is the ETF's exchange country, derived from its exchange
+// when not recorded
+func listingCountry(etf domain.ETF) string {
+	if etf.ExchangeCountry != "" && etf.ExchangeCountry != "UNKNOWN" {
+		return etf.ExchangeCountry
+	}
+	return domain.ExchangeCountry(etf.Exchange)
+}
+
+func hasGeography(etf domain.ETF) bool {
+	return len(etf.GeographicExposure.Regions) > 0 || len(etf.GeographicExposure.Countries) > 0
+}
+
+// mergeRecord combines a refreshed record with the catalogued one under the
+// store's merge policy, recording FieldProvenance and DataConflicts
+func (s *Store) mergeRecord(existing, incoming domain.ETF) domain.ETF {
+	merged := s.mergePolicy.Merge(existing, incoming)
+	merged.DataSources = mergeSources(existing.DataSources, incoming.DataSources)
+	return merged
+}
+
+// mergeSources keeps one entry per source type/provider, preferring the most recent access
+func mergeSources(existing, incoming []domain.DataSource) []domain.DataSource {
+	merged := make([]domain.DataSource, 0, len(existing)+len(incoming))
+	positions := make(map[string]int)
+
+	for _, ds := range append(append([]domain.DataSource{}, existing...), incoming...) {
+		key := ds.Type + "|" + ds.Provider
+		if i, ok := positions[key]; ok {
+			if !ds.AccessDate.Before(merged[i].AccessDate) {
+				merged[i] = ds
+			}
+			continue
+		}
+		positions[key] = len(merged)
+		merged = append(merged, ds)
+	}
+
+	return merged
+}
@@ -0,0 +1,173 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// Assumed when a request does not give an amount or holding period
+const (
+	DefaultAmount = 10000
+	DefaultYears  = 5
+)
+
+// Input is the investment whose costs are estimated
+type Input struct {
+	Amount   float64 // In Currency
+	Years    int     // Holding period; entry and exit costs are spread over it
+	Currency string  // Investor's currency
+	Country  string
+	Platform string
+}
+
+// Model estimates all-in holding costs against a set of brokerage schedules
+type Model struct {
+	schedules *Schedules
+}
+
+func NewModel(schedules *Schedules) *Model {
+	return &Model{schedules: schedules}
+}
+
+// Schedules returns the brokerage schedules the model applies
+func (m *Model) Schedules() *Schedules {
+	return m.schedules
+}
+
+// Estimate returns the annual cost of buying etf with input.Amount, holding
+// it for input.Years and selling it, as % of the amount and in currency
+func (m *Model) Estimate(etf domain.ETF, input Input) domain.CostBreakdown {
+	costs := domain.CostBreakdown{
+		Amount:          input.Amount,
+		Currency:        normalizeCurrency(input.Currency),
+		HoldingYears:    input.Years,
+		Notes:           []string{},
+		ScheduleVersion: m.schedules.Version(),
+	}
+	if costs.Amount <= 0 {
+		costs.Amount = DefaultAmount
+		costs.Notes = append(costs.Notes, fmt.Sprintf("Investment amount not given - assumed %s %d", costs.Currency, DefaultAmount))
+	}
+	if costs.HoldingYears <= 0 {
+		costs.HoldingYears = DefaultYears
+		costs.Notes = append(costs.Notes, fmt.Sprintf("Holding period not given - assumed %d years", DefaultYears))
+	}
+	years := float64(costs.HoldingYears)
+
+	schedule := m.schedules.For(input.Country, input.Platform)
+	costs.Schedule = schedule.ID
+	if input.Platform != "" && schedule.Platform != strings.ToLower(strings.TrimSpace(input.Platform)) {
+		costs.Notes = append(costs.Notes, fmt.Sprintf("No brokerage schedule for %s - used %s", input.Platform, schedule.Name))
+	}
+
+	costs.TER = etf.TER
+	if etf.TER <= 0 {
+		costs.TER = assumedTER(etf.AssetClass)
+		costs.TERAssumed = true
+		class := strings.ToLower(etf.AssetClass)
+		if class == "" {
+			class = "unclassified"
+		}
+		costs.Notes = append(costs.Notes, fmt.Sprintf("TER not reported - assumed %g%% as for %s funds", costs.TER, class))
+	}
+
+	// Half the spread is paid buying and half selling: the full spread once
+	spread := etf.BidAskSpread
+	if spread <= 0 {
+		spread = assumedSpread(etf.AverageDailyVolume)
+		costs.SpreadAssumed = true
+		costs.Notes = append(costs.Notes, fmt.Sprintf("Bid-ask spread not reported - assumed %g%% from trading volume", spread))
+	}
+	costs.Spread = spread / years
+
+	fundCurrency := normalizeCurrency(etf.Currency)
+	switch {
+	case fundCurrency == "":
+		costs.Notes = append(costs.Notes, "Trading currency not reported - no currency conversion counted")
+	case costs.Currency != "" && fundCurrency != costs.Currency:
+		costs.FX = 2 * schedule.FXSpread / years
+		costs.Notes = append(costs.Notes, fmt.Sprintf("Converting %s to %s at %g%% each way", costs.Currency, fundCurrency, schedule.FXSpread))
+	}
+
+	// Fixed fees are only counted in the currency they are charged in
+	fixedFees := schedule.Currency == "" || schedule.Currency == costs.Currency
+	if !fixedFees {
+		costs.Notes = append(costs.Notes, fmt.Sprintf("%s fixed fees are in %s - only percentage fees counted", schedule.Name, schedule.Currency))
+	}
+
+	commission := costs.Amount * schedule.CommissionRate / 100
+	if fixedFees {
+		commission = math.Max(commission, schedule.MinCommission)
+		if schedule.MaxCommission > 0 {
+			commission = math.Min(commission, schedule.MaxCommission)
+		}
+		commission += schedule.TradeFee
+	}
+	costs.Brokerage = 2 * commission / costs.Amount * 100 / years
+
+	costs.PlatformFee = schedule.PlatformFeeRate
+	if fixedFees && schedule.AnnualFee > 0 {
+		costs.PlatformFee += schedule.AnnualFee / costs.Amount * 100
+	}
+
+	costs.EffectiveCost = round3(costs.TER + costs.Spread + costs.FX + costs.Brokerage + costs.PlatformFee)
+	costs.AnnualCost = round2(costs.Amount * costs.EffectiveCost / 100)
+	costs.TotalCost = round2(costs.AnnualCost * years)
+
+	costs.Spread = round3(costs.Spread)
+	costs.FX = round3(costs.FX)
+	costs.Brokerage = round3(costs.Brokerage)
+	costs.PlatformFee = round3(costs.PlatformFee)
+	return costs
+}
+
+// assumedTER stands in for an unreported TER (%): the high end of what funds
+// of the asset class charge, so a fund cannot rank cheaper by not reporting it
+func assumedTER(assetClass string) float64 {
+	class := strings.ToLower(assetClass)
+	switch {
+	case strings.Contains(class, "bond"), strings.Contains(class, "fixed income"):
+		return 0.5
+	case strings.Contains(class, "equit"), strings.Contains(class, "commodit"),
+		strings.Contains(class, "property"), strings.Contains(class, "real estate"):
+		return 0.75
+	}
+	return 1.0
+}
+
+// assumedSpread stands in for an unreported bid-ask spread (%), wider for
+// thinly traded funds
+func assumedSpread(avgVolume float64) float64 {
+	switch {
+	case avgVolume >= 500000:
+		return 0.1
+	case avgVolume >= 100000:
+		return 0.25
+	case avgVolume >= 10000:
+		return 0.5
+	}
+	return 1.0
+}
+
+// normalizeCurrency maps quote units such as ZAc and GBp to their currency
+func normalizeCurrency(currency string) string {
+	switch upper := strings.ToUpper(strings.TrimSpace(currency)); upper {
+	case "ZAC":
+		return "ZAR"
+	case "GBX":
+		return "GBP"
+	default:
+		return upper
+	}
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func round3(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,160 @@
+// Package cost estimates the all-in annual cost of holding an ETF: its TER,
+// the bid-ask spread and currency conversion paid on entry and exit, and the
+// brokerage and platform fees of the investor's broker.
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Schedule is one broker's or platform's fees. Rates are %; fixed fees are
+// in Currency.
+type Schedule struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Country         string  `json:"country,omitempty"`  // Investor country it applies to; empty for any
+	Platform        string  `json:"platform,omitempty"` // Matches InvestorProfile.platform; empty for the country default
+	Currency        string  `json:"currency,omitempty"` // Required when any fixed fee is set
+	CommissionRate  float64 `json:"commissionRate"`     // % of each trade
+	MinCommission   float64 `json:"minCommission,omitempty"`
+	MaxCommission   float64 `json:"maxCommission,omitempty"` // 0 for no cap
+	TradeFee        float64 `json:"tradeFee,omitempty"`      // Fixed fee per trade
+	PlatformFeeRate float64 `json:"platformFeeRate,omitempty"`
+	AnnualFee       float64 `json:"annualFee,omitempty"` // Fixed account fee per year
+	FXSpread        float64 `json:"fxSpread"`            // % lost converting currency, each way
+	Reference       string  `json:"reference,omitempty"`
+}
+
+// Schedules is the set of brokerage schedules costs are estimated with
+type Schedules struct {
+	version   string
+	schedules []Schedule
+	path      string
+}
+
+// DefaultSchedules returns a single schedule for any investor, used when no
+// schedules file is configured
+func DefaultSchedules() *Schedules {
+	return &Schedules{
+		version: "brokerage_builtin",
+		schedules: []Schedule{{
+			ID:             "default",
+			Name:           "Typical online broker",
+			CommissionRate: 0.25,
+			FXSpread:       0.5,
+		}},
+	}
+}
+
+// LoadSchedules reads a schedules file: {"version": "...", "schedules": [...]}
+func LoadSchedules(path string) (*Schedules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read brokerage schedules: %w", err)
+	}
+
+	schedules, err := ParseSchedules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	schedules.path = path
+	return schedules, nil
+}
+
+// ParseSchedules decodes and validates a schedules file
+func ParseSchedules(data []byte) (*Schedules, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+
+	var file struct {
+		Version   string     `json:"version"`
+		Schedules []Schedule `json:"schedules"`
+	}
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode brokerage schedules: %w", err)
+	}
+
+	switch {
+	case file.Version == "":
+		return nil, fmt.Errorf("version is required")
+	case len(file.Schedules) == 0:
+		return nil, fmt.Errorf("at least one schedule is required")
+	}
+
+	seen := make(map[string]bool, len(file.Schedules))
+	for i := range file.Schedules {
+		s := &file.Schedules[i]
+		s.Country = strings.ToUpper(strings.TrimSpace(s.Country))
+		s.Platform = strings.ToLower(strings.TrimSpace(s.Platform))
+		s.Currency = strings.ToUpper(strings.TrimSpace(s.Currency))
+
+		fixed := s.MinCommission > 0 || s.MaxCommission > 0 || s.TradeFee > 0 || s.AnnualFee > 0
+		switch {
+		case s.ID == "":
+			return nil, fmt.Errorf("schedule %d: id is required", i)
+		case seen[s.ID]:
+			return nil, fmt.Errorf("%s: duplicate schedule id", s.ID)
+		case s.CommissionRate < 0 || s.PlatformFeeRate < 0 || s.FXSpread < 0:
+			return nil, fmt.Errorf("%s: rates cannot be negative", s.ID)
+		case s.MinCommission < 0 || s.MaxCommission < 0 || s.TradeFee < 0 || s.AnnualFee < 0:
+			return nil, fmt.Errorf("%s: fees cannot be negative", s.ID)
+		case s.MaxCommission > 0 && s.MaxCommission < s.MinCommission:
+			return nil, fmt.Errorf("%s: maxCommission is below minCommission", s.ID)
+		case fixed && s.Currency == "":
+			return nil, fmt.Errorf("%s: currency is required for fixed fees", s.ID)
+		}
+		seen[s.ID] = true
+	}
+
+	return &Schedules{version: file.Version, schedules: file.Schedules}, nil
+}
+
+// For picks the schedule for an investor: the platform's own schedule, then
+// the country default, then a schedule for any country. The first schedule
+// is used when none match.
+func (s *Schedules) For(country, platform string) Schedule {
+	country = strings.ToUpper(country)
+	platform = strings.ToLower(strings.TrimSpace(platform))
+
+	var countryDefault, anyCountry *Schedule
+	for i := range s.schedules {
+		schedule := &s.schedules[i]
+		countryMatches := schedule.Country == "" || schedule.Country == country
+		switch {
+		case platform != "" && schedule.Platform == platform && countryMatches:
+			return *schedule
+		case schedule.Platform != "":
+			continue
+		case schedule.Country == country && countryDefault == nil:
+			countryDefault = schedule
+		case schedule.Country == "" && anyCountry == nil:
+			anyCountry = schedule
+		}
+	}
+
+	switch {
+	case countryDefault != nil:
+		return *countryDefault
+	case anyCountry != nil:
+		return *anyCountry
+	}
+	return s.schedules[0]
+}
+
+// Version identifies the loaded schedules
+func (s *Schedules) Version() string {
+	return s.version
+}
+
+// List returns every schedule
+func (s *Schedules) List() []Schedule {
+	return append([]Schedule(nil), s.schedules...)
+}
+
+// Path is the file the schedules were loaded from, if any
+func (s *Schedules) Path() string {
+	return s.path
+}
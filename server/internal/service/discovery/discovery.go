@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,9 +10,12 @@ import (
 
 	"upstonk/internal/api/dto"
 	"upstonk/internal/domain"
+	"upstonk/internal/service/catalog"
+	"upstonk/internal/service/cost"
 	"upstonk/internal/service/eligibility"
 	"upstonk/internal/service/ranking"
 	"upstonk/internal/service/search"
+	"upstonk/internal/service/tax"
 )
 
 // Service orchestrates ETF discovery workflow
@@ -19,6 +23,9 @@ type Service struct {
 	searchService     search.Provider
 	eligibilityEngine eligibility.Engine
 	rankingEngine     ranking.Engine
+	taxEstimator      *tax.Estimator
+	costModel         *cost.Model
+	catalog           *catalog.Store
 	cacheEnabled      bool
 }
 
@@ -31,24 +38,45 @@ func NewService(
 		searchService:     searchProvider,
 		eligibilityEngine: eligibilityEngine,
 		rankingEngine:     rankingEngine,
+		taxEstimator:      tax.NewEstimator(),
+		costModel:         cost.NewModel(cost.DefaultSchedules()),
 		cacheEnabled:      true,
 	}
 }
 
+// SetCatalog lets warnings suggest alternatives from the whole catalog
+// rather than only the candidates a search returned
+func (s *Service) SetCatalog(store *catalog.Store) {
+	s.catalog = store
+}
+
+// SetCostModel replaces the built-in brokerage schedules used to estimate
+// holding costs
+func (s *Service) SetCostModel(model *cost.Model) {
+	s.costModel = model
+}
+
 type DiscoveryResult struct {
 	Results      []dto.ETFResult
 	Alternatives []dto.ETFResult
 	Summary      dto.SearchSummary
 	Warnings     []dto.Warning
+	Weights      dto.RankingWeights // Component weights the ranking used
 	CacheHit     bool
+	Stale        bool
+	DataAsOf     string
 }
 
 // DiscoverETFs is the main workflow orchestrator
 func (s *Service) DiscoverETFs(ctx context.Context, req dto.DiscoveryRequest) (*DiscoveryResult, error) {
 	startTime := time.Now()
+	ctx, trace := search.WithTrace(ctx)
 
 	// Step 1: Search for candidate ETFs
 	candidates, err := s.searchCandidates(ctx, req)
+	if errors.Is(err, search.ErrNoSourceAvailable) {
+		return nil, &DataSourceError{Source: strings.Join(sourceNames(trace.Sources()), ", "), Err: err}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
@@ -59,8 +87,9 @@ func (s *Service) DiscoverETFs(ctx context.Context, req dto.DiscoveryRequest) (*
 		}
 	}
 
-	// Step 2: Evaluate eligibility for each candidate
-	evaluatedETFs, summary := s.evaluateEligibility(ctx, candidates, req.InvestorProfile)
+	// Step 2: Evaluate eligibility and dividend tax drag for each candidate
+	evaluatedETFs, summary := s.evaluateEligibility(ctx, candidates, req.InvestorProfile, evaluationDate(req.AsOf))
+	summary.DataSourcesQueried, summary.DataSources = summarizeSources(trace.Sources())
 
 	// Step 3: Filter based on constraints
 	filtered := s.applyConstraints(evaluatedETFs, req.Constraints)
@@ -69,13 +98,29 @@ func (s *Service) DiscoverETFs(ctx context.Context, req dto.DiscoveryRequest) (*
 	scored := s.calculateMatchScores(filtered, req.Exposure)
 
 	// Step 5: Rank using weighted scoring
-	ranked := s.rankETFs(scored, req.RankingPreferences)
+	weights, err := ranking.ResolveWeights(req.RankingPreferences)
+	if err != nil {
+		return nil, fmt.Errorf("ranking preferences: %w", err)
+	}
+	ranked := s.rankETFs(scored, weights)
 
 	// Step 6: Build output
 	results, alternatives := s.buildOutput(ranked, req.OutputOptions)
 
 	// Step 7: Generate warnings
 	warnings := s.generateWarnings(results, req)
+	warnings = append(warnings, s.situsWarnings(results, candidates, req.InvestorProfile)...)
+
+	stale, dataAsOf := trace.Stale()
+	var dataAsOfText string
+	if stale {
+		dataAsOfText = dataAsOf.UTC().Format(time.RFC3339)
+		warnings = append(warnings, dto.Warning{
+			Code:     "STALE_DATA",
+			Message:  fmt.Sprintf("Results are from data fetched at %s and are being refreshed in the background.", dataAsOfText),
+			Severity: "info",
+		})
+	}
 
 	searchDuration := time.Since(startTime).Milliseconds()
 
@@ -87,11 +132,16 @@ func (s *Service) DiscoverETFs(ctx context.Context, req dto.DiscoveryRequest) (*
 			TotalEligible:      summary.TotalEligible,
 			TotalIneligible:    summary.TotalIneligible,
 			TotalUnknown:       summary.TotalUnknown,
+			TotalNotOffered:    summary.TotalNotOffered,
 			SearchDurationMs:   searchDuration,
 			DataSourcesQueried: summary.DataSourcesQueried,
+			DataSources:        summary.DataSources,
 		},
 		Warnings: warnings,
-		CacheHit: false,
+		Weights:  dto.RankingWeights{Method: weights.Method, Weights: weights.Values},
+		CacheHit: trace.CacheHit(),
+		Stale:    stale,
+		DataAsOf: dataAsOfText,
 	}, nil
 }
 
@@ -103,6 +153,7 @@ func (s *Service) searchCandidates(ctx context.Context, req dto.DiscoveryRequest
 		Companies:    req.Exposure.Assets.Companies,
 		Country:      req.InvestorProfile.Country,
 		Vehicles:     req.InvestmentVehicles,
+		Exchanges:    req.Constraints.AllowedExchanges,
 	}
 
 	return s.searchService.Search(ctx, searchCriteria)
@@ -112,21 +163,34 @@ func (s *Service) evaluateEligibility(
 	ctx context.Context,
 	etfs []domain.ETF,
 	profile dto.InvestorProfile,
+	asOf time.Time,
 ) ([]domain.DiscoveredETF, EligibilitySummary) {
 
 	summary := EligibilitySummary{
-		TotalSearched:      len(etfs),
-		DataSourcesQueried: []string{"JSE", "FactSheets", "Provider APIs"},
+		TotalSearched: len(etfs),
+	}
+	if profile.Platform != "" {
+		ctx = eligibility.WithPlatform(ctx, profile.Platform)
 	}
 
 	discovered := make([]domain.DiscoveredETF, 0, len(etfs))
 
 	for _, etf := range etfs {
-		eligibility := s.eligibilityEngine.Evaluate(ctx, etf, profile.Country, profile.AccountType)
+		eligibility := s.eligibilityEngine.EvaluateAsOf(ctx, etf, profile.Country, profile.AccountType, asOf)
+		taxDrag := s.taxEstimator.Estimate(etf, profile.Country, profile.AccountType)
+		costs := s.costModel.Estimate(etf, cost.Input{
+			Amount:   profile.InvestmentAmount,
+			Years:    profile.TimeHorizonYears,
+			Currency: profile.Currency,
+			Country:  profile.Country,
+			Platform: profile.Platform,
+		})
 
 		discovered = append(discovered, domain.DiscoveredETF{
 			ETF:         etf,
 			Eligibility: eligibility,
+			TaxDrag:     &taxDrag,
+			Costs:       &costs,
 		})
 
 		// Update summary counts
@@ -137,6 +201,8 @@ func (s *Service) evaluateEligibility(
 			summary.TotalIneligible++
 		case domain.StatusUnknown:
 			summary.TotalUnknown++
+		case domain.StatusNotOffered:
+			summary.TotalNotOffered++
 		}
 	}
 
@@ -314,10 +380,10 @@ func (s *Service) calculateCompanyMatch(etf domain.ETF, companies []string) floa
 	return min(matchedWeight/100.0, 1.0)
 }
 
-func (s *Service) rankETFs(etfs []domain.DiscoveredETF, preferences dto.RankingPreferences) []domain.DiscoveredETF {
+func (s *Service) rankETFs(etfs []domain.DiscoveredETF, weights ranking.Weights) []domain.DiscoveredETF {
 	// Use ranking engine for weighted scoring
 	for i := range etfs {
-		rankingScore := s.rankingEngine.Score(etfs[i].ETF, preferences)
+		rankingScore := s.rankingEngine.Score(etfs[i], weights)
 		etfs[i].Ranking = rankingScore
 	}
 
@@ -377,6 +443,9 @@ func (s *Service) toETFResult(discovered domain.DiscoveredETF, options dto.Outpu
 			Confidence:    string(discovered.Eligibility.Confidence),
 			Justification: formatJustification(discovered.Eligibility),
 			RuleVersion:   discovered.Eligibility.RuleVersion,
+			AsOf:          discovered.Eligibility.AsOf.UTC().Format("2006-01-02"),
+
+			EvidenceCompleteness: discovered.Eligibility.EvidenceCompleteness,
 		},
 	}
 
@@ -385,6 +454,53 @@ func (s *Service) toETFResult(discovered domain.DiscoveredETF, options dto.Outpu
 		result.Eligibility.RulesPassed = discovered.Eligibility.RulesPassed
 		result.Eligibility.RulesFailed = discovered.Eligibility.RulesFailed
 		result.Eligibility.Warnings = extractWarnings(discovered.Eligibility)
+		result.Eligibility.Evidence = toEvidenceItems(discovered.Eligibility.Evidence)
+		if outcomes := discovered.Eligibility.RuleResults; len(outcomes) > 1 {
+			for _, outcome := range outcomes {
+				result.Eligibility.RuleResults = append(result.Eligibility.RuleResults, dto.RuleResult{
+					Rule:       outcome.Rule,
+					Version:    outcome.Version,
+					Status:     string(outcome.Status),
+					Confidence: string(outcome.Confidence),
+				})
+			}
+		}
+	}
+
+	if drag := discovered.TaxDrag; drag != nil {
+		result.TaxDrag = &dto.TaxDrag{
+			FundDomicile:      drag.FundDomicile,
+			DividendYield:     drag.DividendYield,
+			YieldAssumed:      drag.YieldAssumed,
+			FundLevelRate:     drag.FundLevelRate,
+			InvestorLevelRate: drag.InvestorLevelRate,
+			EffectiveRate:     drag.EffectiveRate,
+			AnnualDrag:        drag.AnnualDrag,
+			Confidence:        string(drag.Confidence),
+			Notes:             drag.Notes,
+			TableVersion:      drag.TableVersion,
+		}
+	}
+
+	if costs := discovered.Costs; costs != nil {
+		result.Costs = &dto.CostBreakdown{
+			Amount:          costs.Amount,
+			Currency:        costs.Currency,
+			HoldingYears:    costs.HoldingYears,
+			TER:             costs.TER,
+			TERAssumed:      costs.TERAssumed,
+			Spread:          costs.Spread,
+			SpreadAssumed:   costs.SpreadAssumed,
+			FX:              costs.FX,
+			Brokerage:       costs.Brokerage,
+			PlatformFee:     costs.PlatformFee,
+			EffectiveCost:   costs.EffectiveCost,
+			AnnualCost:      costs.AnnualCost,
+			TotalCost:       costs.TotalCost,
+			Schedule:        costs.Schedule,
+			ScheduleVersion: costs.ScheduleVersion,
+			Notes:           costs.Notes,
+		}
 	}
 
 	// Add holdings and breakdowns
@@ -393,6 +509,7 @@ func (s *Service) toETFResult(discovered domain.DiscoveredETF, options dto.Outpu
 		Bonds:       etf.AssetExposure.Bonds,
 		Cash:        etf.AssetExposure.Cash,
 		Commodities: etf.AssetExposure.Commodities,
+		RealEstate:  etf.AssetExposure.RealEstate,
 		Other:       etf.AssetExposure.Other,
 	}
 
@@ -420,6 +537,23 @@ func (s *Service) toETFResult(discovered domain.DiscoveredETF, options dto.Outpu
 				Date:     ds.AccessDate.Format("2006-01-02"),
 			})
 		}
+
+		if len(etf.FieldProvenance) > 0 {
+			result.FieldProvenance = make(map[string]dto.SourceReference, len(etf.FieldProvenance))
+			for field, ds := range etf.FieldProvenance {
+				result.FieldProvenance[field] = dto.SourceReference{
+					Type:     ds.Type,
+					Provider: ds.Provider,
+					URL:      ds.URL,
+					Date:     ds.AccessDate.Format("2006-01-02"),
+				}
+			}
+		}
+	}
+
+	// Source disagreements are always surfaced
+	for _, conflict := range etf.DataConflicts {
+		result.DataConflicts = append(result.DataConflicts, search.FormatConflict(conflict))
 	}
 
 	return result
@@ -453,10 +587,142 @@ func (s *Service) generateWarnings(results []dto.ETFResult, req dto.DiscoveryReq
 		})
 	}
 
+	// Flag results whose providers disagree
+	for _, result := range results {
+		if len(result.DataConflicts) == 0 {
+			continue
+		}
+		warnings = append(warnings, dto.Warning{
+			Code: "DATA_CONFLICT",
+			Message: fmt.Sprintf("Data sources disagree for %s: %s",
+				result.Ticker, strings.Join(result.DataConflicts, "; ")),
+			Severity: "warning",
+		})
+	}
+
+	return warnings
+}
+
+// situsWarnings flags results whose domicile exposes the investor to a
+// foreign estate tax, with catalog funds tracking the same index that avoid it
+func (s *Service) situsWarnings(results []dto.ETFResult, candidates []domain.ETF, profile dto.InvestorProfile) []dto.Warning {
+	byTicker := make(map[string]domain.ETF, len(candidates))
+	for _, etf := range candidates {
+		byTicker[etf.Ticker] = etf
+	}
+
+	pool := candidates
+	if s.catalog != nil {
+		pool = s.catalog.All()
+	}
+
+	warnings := []dto.Warning{}
+	for _, result := range results {
+		etf, ok := byTicker[result.Ticker]
+		if !ok {
+			continue
+		}
+		exposure, exposed := tax.SitusExposure(etf, profile.Country)
+		if !exposed {
+			continue
+		}
+
+		warning := dto.Warning{
+			Code:     exposure.Jurisdiction + "_ESTATE_TAX_SITUS",
+			Message:  exposure.Reason + ".",
+			Severity: "warning",
+			Ticker:   etf.Ticker,
+		}
+		alternatives := tax.SitusAlternatives(etf, pool, profile.Country, 3)
+		for _, alternative := range alternatives {
+			warning.Alternatives = append(warning.Alternatives, dto.AlternativeETF{
+				Ticker:   alternative.Ticker,
+				Name:     alternative.Name,
+				ISIN:     alternative.ISIN,
+				Exchange: alternative.Exchange,
+				Domicile: alternative.Domicile,
+				TER:      alternative.TER,
+			})
+		}
+		if len(alternatives) > 0 {
+			warning.Message += fmt.Sprintf(" Funds tracking the same index domiciled elsewhere avoid this: %s.", alternativeTickers(alternatives))
+		}
+		warnings = append(warnings, warning)
+	}
 	return warnings
 }
 
 // Helper functions
+
+func alternativeTickers(etfs []domain.ETF) string {
+	tickers := make([]string, 0, len(etfs))
+	for _, etf := range etfs {
+		tickers = append(tickers, fmt.Sprintf("%s (%s)", etf.Ticker, etf.Domicile))
+	}
+	return strings.Join(tickers, ", ")
+}
+
+// evaluationDate parses a request's asOf date, defaulting to now. The request
+// validator has already checked the format.
+func evaluationDate(asOf string) time.Time {
+	if date, err := time.Parse("2006-01-02", asOf); err == nil {
+		return date
+	}
+	return time.Now()
+}
+
+// summarizeSources lists the sources actually called and the outcome of each
+func summarizeSources(sources []search.SourceStatus) ([]string, []dto.DataSourceStatus) {
+	queried := []string{}
+	statuses := make([]dto.DataSourceStatus, 0, len(sources))
+
+	for _, source := range sources {
+		if source.Status != search.SourceSkipped {
+			queried = append(queried, source.Name)
+		}
+		statuses = append(statuses, dto.DataSourceStatus{
+			Name:       source.Name,
+			Status:     source.Status,
+			Results:    source.Results,
+			DurationMs: source.Duration.Milliseconds(),
+			Error:      source.Error,
+		})
+	}
+
+	return queried, statuses
+}
+
+func sourceNames(sources []search.SourceStatus) []string {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+	return names
+}
+
+func toEvidenceItems(evidence []domain.EligibilityEvidence) []dto.EvidenceItem {
+	items := make([]dto.EvidenceItem, 0, len(evidence))
+	for _, e := range evidence {
+		item := dto.EvidenceItem{
+			Rule:      e.Rule,
+			Criterion: e.Criterion,
+			Expected:  e.Expected,
+			Actual:    e.Actual,
+			Result:    e.Result,
+		}
+		if e.DataSource.Type != "" || e.DataSource.Provider != "" {
+			item.Source = &dto.SourceReference{
+				Type:     e.DataSource.Type,
+				Provider: e.DataSource.Provider,
+				URL:      e.DataSource.URL,
+				Date:     e.DataSource.AccessDate.Format("2006-01-02"),
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func formatJustification(eligibility domain.EligibilityResult) string {
 	if len(eligibility.Reasons) == 0 {
 		return "Eligibility could not be determined"
@@ -537,5 +803,7 @@ type EligibilitySummary struct {
 	TotalEligible      int
 	TotalIneligible    int
 	TotalUnknown       int
+	TotalNotOffered    int
 	DataSourcesQueried []string
+	DataSources        []dto.DataSourceStatus
 }
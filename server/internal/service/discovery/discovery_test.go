@@ -0,0 +1,167 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"upstonk/internal/api/dto"
+	"upstonk/internal/service/catalog"
+	"upstonk/internal/service/eligibility"
+	"upstonk/internal/service/eligibility/rules"
+	"upstonk/internal/service/ranking"
+	"upstonk/internal/service/search"
+)
+
+// fixtureDir holds recorded Yahoo Finance responses for STX40 and STXSWX
+const fixtureDir = "../../../testdata/fixtures"
+
+// rulesDir is the shipped declarative rule sets, registered over the built-ins
+// as in production
+const rulesDir = "../../../data/rules"
+
+// newReplayService returns a service searching the fixtures through an
+// uncached aggregated provider, and the version of the shipped TFSA_ZA rule file
+func newReplayService(t *testing.T) (*Service, string) {
+	t.Helper()
+
+	engine, tfsaVersion := newReplayEngine(t)
+	return NewService(search.NewAggregatedProvider(nil, newReplayLive()), engine, ranking.NewWeightedScorer()), tfsaVersion
+}
+
+// newReplayLive returns the live provider over the recorded fixtures
+func newReplayLive() search.Provider {
+	universe := search.NewUniverse("fixtures",
+		search.UniverseEntry{Ticker: "STX40", Exchange: "JSE", Symbol: "STX40.JO", AssetClass: "equity", Regions: []string{"south africa"}, Sources: []string{"yahoo"}},
+		search.UniverseEntry{Ticker: "STXSWX", Exchange: "JSE", Symbol: "STXSWX.JO", AssetClass: "equity", Regions: []string{"south africa"}, Sources: []string{"yahoo"}},
+	)
+	client := search.NewHTTPClient(search.FixtureModeReplay, fixtureDir)
+	return search.NewMonitoredProvider("live", search.NewLiveProvider(client, universe), search.DefaultBreakerConfig())
+}
+
+// newReplayEngine registers the built-in TFSA rules and the shipped rule files,
+// returning the version of the TFSA_ZA file
+func newReplayEngine(t *testing.T) (eligibility.Engine, string) {
+	t.Helper()
+
+	engine := eligibility.NewEngine()
+	engine.SetPolicy(eligibility.PolicyStrictest)
+	engine.RegisterRule(rules.NewTFSASouthAfricaRules())
+	declarative, err := rules.LoadDeclarativeRules(rulesDir)
+	if err != nil {
+		t.Fatalf("load rule files: %v", err)
+	}
+	tfsaVersion := ""
+	for _, rule := range declarative {
+		engine.RegisterRule(rule)
+		if rule.Name() == "TFSA_ZA" {
+			tfsaVersion = rule.Version()
+		}
+	}
+	if tfsaVersion == "" {
+		t.Fatalf("no TFSA_ZA rule file in %s", rulesDir)
+	}
+	return engine, tfsaVersion
+}
+
+// replayRequest is a ZA TFSA search matching both fixture funds
+func replayRequest() dto.DiscoveryRequest {
+	return dto.DiscoveryRequest{
+		InvestorProfile: dto.InvestorProfile{Country: "ZA", AccountType: "tfsa", Currency: "ZAR"},
+		Exposure: dto.ExposureRequest{
+			Geography: dto.GeographyExposureRequest{Markets: []string{"south africa"}},
+		},
+		InvestmentVehicles: []string{"etf"},
+		RankingPreferences: dto.RankingPreferences{Priority: []string{"lowest_fees"}},
+		OutputOptions:      dto.OutputOptions{MaxResults: 10},
+		AsOf:               "2026-10-01", // After the shipped TFSA_ZA file takes effect
+	}
+}
+
+func TestDiscoverETFsReplay(t *testing.T) {
+	service, tfsaVersion := newReplayService(t)
+	req := replayRequest()
+
+	result, err := service.DiscoverETFs(context.Background(), req)
+	if err != nil {
+		t.Fatalf("DiscoverETFs: %v", err)
+	}
+
+	if len(result.Results) != 2 {
+		t.Fatalf("got %d results, want 2 (STX40, STXSWX); warnings: %+v", len(result.Results), result.Warnings)
+	}
+
+	ters := map[string]float64{"STX40": 0.1, "STXSWX": 0.15}
+	for _, etf := range result.Results {
+		want, ok := ters[etf.Ticker]
+		if !ok {
+			t.Errorf("unexpected ticker %s", etf.Ticker)
+			continue
+		}
+		if etf.Exchange != "JSE" {
+			t.Errorf("%s: exchange %q, want JSE", etf.Ticker, etf.Exchange)
+		}
+		if etf.TER != want {
+			t.Errorf("%s: TER %.2f, want %.2f", etf.Ticker, etf.TER, want)
+		}
+		// Yahoo quotes JSE funds in cents (ZAc); the shipped rule file must accept them
+		if etf.Eligibility.Status != "eligible" || etf.Eligibility.RuleVersion != tfsaVersion {
+			t.Errorf("%s: %s under %s, want eligible under %s (failed: %v)", etf.Ticker,
+				etf.Eligibility.Status, etf.Eligibility.RuleVersion, tfsaVersion, etf.Eligibility.RulesFailed)
+		}
+	}
+
+	// Cheaper fund ranks first when fees are the only priority
+	if result.Results[0].Ticker != "STX40" {
+		t.Errorf("top result %s, want STX40", result.Results[0].Ticker)
+	}
+	if result.Weights.Method != ranking.MethodPriority {
+		t.Errorf("weights method %q, want %q", result.Weights.Method, ranking.MethodPriority)
+	}
+}
+
+// The result cache sits behind the catalog: it only fills while the catalog
+// falls back to the live providers, and once the catalog answers a search it
+// is never consulted, so cacheHit is only reported with the catalog disabled.
+func TestDiscoverETFsCacheReporting(t *testing.T) {
+	engine, _ := newReplayEngine(t)
+	cached := func() *search.AggregatedProvider {
+		return search.NewAggregatedProvider(search.NewETFCache(10, time.Hour, time.Hour), newReplayLive())
+	}
+
+	tests := []struct {
+		name     string
+		provider search.Provider
+		wantHit  bool
+	}{
+		{"catalog disabled", cached(), true},
+		{"catalog enabled", catalog.NewProvider(catalog.NewMemoryStore(), cached()), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(tt.provider, engine, ranking.NewWeightedScorer())
+			first, err := service.DiscoverETFs(context.Background(), replayRequest())
+			if err != nil {
+				t.Fatalf("first DiscoverETFs: %v", err)
+			}
+			if first.CacheHit {
+				t.Errorf("first search reported a cache hit")
+			}
+
+			second, err := service.DiscoverETFs(context.Background(), replayRequest())
+			if err != nil {
+				t.Fatalf("second DiscoverETFs: %v", err)
+			}
+			if second.CacheHit != tt.wantHit {
+				t.Errorf("second search cacheHit %v, want %v", second.CacheHit, tt.wantHit)
+			}
+			if second.Stale {
+				t.Errorf("second search reported stale results")
+			}
+			if len(second.Results) != len(first.Results) {
+				t.Errorf("second search returned %d results, first %d", len(second.Results), len(first.Results))
+			}
+		})
+	}
+}
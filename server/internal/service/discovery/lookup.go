@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"upstonk/internal/api/dto"
+	"upstonk/internal/domain"
+	"upstonk/internal/service/eligibility"
+	"upstonk/internal/service/search"
+)
+
+// InstrumentEligibility is the eligibility of one requested instrument. ETF
+// and Eligibility are nil when the identifier could not be resolved.
+type InstrumentEligibility struct {
+	Identifier  string
+	ETF         *domain.ETF
+	Eligibility *domain.EligibilityResult
+}
+
+// ExplainEligibility resolves each ticker or ISIN through the search layer and
+// evaluates it for the queried account, without ranking or filtering. The live
+// universe is keyed by ticker, so ISINs only resolve for catalogued funds.
+func (s *Service) ExplainEligibility(ctx context.Context, identifiers []string, query dto.EligibilityQuery) ([]InstrumentEligibility, error) {
+	ctx, trace := search.WithTrace(ctx)
+
+	etfs, err := s.searchService.Search(ctx, search.Criteria{
+		Identifiers: identifiers,
+		Country:     query.Country,
+	})
+	if errors.Is(err, search.ErrNoSourceAvailable) {
+		return nil, &DataSourceError{Source: strings.Join(sourceNames(trace.Sources()), ", "), Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup failed: %w", err)
+	}
+
+	if query.Platform != "" {
+		ctx = eligibility.WithPlatform(ctx, query.Platform)
+	}
+	asOf := evaluationDate(query.AsOf)
+
+	results := make([]InstrumentEligibility, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		result := InstrumentEligibility{Identifier: identifier}
+		if etf, ok := findInstrument(etfs, identifier); ok {
+			evaluated := s.eligibilityEngine.EvaluateAsOf(ctx, etf, query.Country, query.AccountType, asOf)
+			result.ETF = &etf
+			result.Eligibility = &evaluated
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// findInstrument matches a ticker (with or without exchange suffix) or ISIN
+func findInstrument(etfs []domain.ETF, identifier string) (domain.ETF, bool) {
+	id := strings.ToUpper(strings.TrimSpace(identifier))
+	idBase, _, _ := strings.Cut(id, ".")
+	for _, etf := range etfs {
+		ticker := strings.ToUpper(etf.Ticker)
+		base, _, _ := strings.Cut(ticker, ".")
+		if ticker == id || base == id || ticker == idBase || (etf.ISIN != "" && strings.EqualFold(etf.ISIN, id)) {
+			return etf, true
+		}
+	}
+	return domain.ETF{}, false
+}
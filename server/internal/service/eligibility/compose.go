@@ -0,0 +1,143 @@
+package eligibility
+
+import (
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// CompositionPolicy decides how the results of several applicable rule sets
+// (e.g. the statutory rules and a platform's approved list) combine
+type CompositionPolicy string
+
+const (
+	// PolicyStrictest reports the most restrictive status of any rule set
+	PolicyStrictest CompositionPolicy = "strictest"
+	// PolicyAnyFail lets any rule set veto: a single ineligible result makes the
+	// ETF ineligible, otherwise the first registered rule set decides
+	PolicyAnyFail CompositionPolicy = "any_fail"
+	// PolicyFirstMatch evaluates only the first applicable rule set
+	PolicyFirstMatch CompositionPolicy = "first_match"
+)
+
+// statusSeverity orders statuses from least to most restrictive
+var statusSeverity = map[domain.EligibilityStatus]int{
+	domain.StatusEligible:    0,
+	domain.StatusConditional: 1,
+	domain.StatusUnknown:     2,
+	domain.StatusIneligible:  3,
+}
+
+// compose merges per-rule results into one. Criteria and evidence keep the
+// rule that recorded them; with more than one rule set, criterion names are
+// qualified as RULE.criterion so identically named checks stay distinct.
+func compose(policy CompositionPolicy, rules []Rule, results []domain.EligibilityResult) domain.EligibilityResult {
+	combined := domain.EligibilityResult{
+		EvaluatedAt:  results[0].EvaluatedAt,
+		Confidence:   domain.ConfidenceHigh,
+		Reasons:      []string{},
+		RulesPassed:  []string{},
+		RulesFailed:  []string{},
+		RulesSkipped: []string{},
+		Evidence:     []domain.EligibilityEvidence{},
+		RuleResults:  make([]domain.RuleOutcome, 0, len(results)),
+	}
+
+	qualify := len(results) > 1
+	versions := make([]string, 0, len(results))
+	seenReasons := make(map[string]bool)
+
+	for i, result := range results {
+		name := rules[i].Name()
+		versions = append(versions, result.RuleVersion)
+		combined.RuleResults = append(combined.RuleResults, domain.RuleOutcome{
+			Rule:       name,
+			Version:    result.RuleVersion,
+			Status:     result.Status,
+			Confidence: result.Confidence,
+		})
+
+		criterionName := func(criterion string) string {
+			if qualify {
+				return name + "." + criterion
+			}
+			return criterion
+		}
+		for _, criterion := range result.RulesPassed {
+			combined.RulesPassed = append(combined.RulesPassed, criterionName(criterion))
+		}
+		for _, criterion := range result.RulesFailed {
+			combined.RulesFailed = append(combined.RulesFailed, criterionName(criterion))
+		}
+		for _, criterion := range result.RulesSkipped {
+			combined.RulesSkipped = append(combined.RulesSkipped, criterionName(criterion))
+		}
+		for _, evidence := range result.Evidence {
+			evidence.Rule = name
+			combined.Evidence = append(combined.Evidence, evidence)
+		}
+
+		// Rule sets often share checks such as leverage; report each reason once
+		for _, reason := range result.Reasons {
+			if !seenReasons[reason] {
+				seenReasons[reason] = true
+				combined.Reasons = append(combined.Reasons, reason)
+			}
+		}
+
+		if domain.ConfidenceRank[result.Confidence] < domain.ConfidenceRank[combined.Confidence] {
+			combined.Confidence = result.Confidence
+		}
+	}
+
+	combined.RuleVersion = strings.Join(versions, "+")
+	combined.Status = composeStatus(policy, results)
+	combined.IsEligible = combined.Status == domain.StatusEligible || combined.Status == domain.StatusConditional
+	combined.Confidence = evidenceConfidence(combined.Confidence, combined.Evidence)
+
+	return combined
+}
+
+func composeStatus(policy CompositionPolicy, results []domain.EligibilityResult) domain.EligibilityStatus {
+	if policy == PolicyAnyFail {
+		for _, result := range results {
+			if result.Status == domain.StatusIneligible {
+				return domain.StatusIneligible
+			}
+		}
+		return results[0].Status
+	}
+
+	status := results[0].Status
+	for _, result := range results[1:] {
+		if statusSeverity[result.Status] > statusSeverity[status] {
+			status = result.Status
+		}
+	}
+	return status
+}
+
+// evidenceConfidence caps the lowest rule confidence by how much of the
+// combined evidence is unverified: any unknown criterion allows at most
+// medium confidence, and a majority of unknowns allows at most low
+func evidenceConfidence(confidence domain.ConfidenceLevel, evidence []domain.EligibilityEvidence) domain.ConfidenceLevel {
+	unknown := 0
+	for _, item := range evidence {
+		if item.Result == "unknown" {
+			unknown++
+		}
+	}
+
+	limit := domain.ConfidenceHigh
+	switch {
+	case unknown*2 > len(evidence):
+		limit = domain.ConfidenceLow
+	case unknown > 0:
+		limit = domain.ConfidenceMedium
+	}
+
+	if domain.ConfidenceRank[confidence] > domain.ConfidenceRank[limit] {
+		return limit
+	}
+	return confidence
+}
@@ -3,30 +3,101 @@ package eligibility
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
 	"upstonk/internal/domain"
 )
 
+// DefaultEngine selects, for each rule name, the version in effect on the
+// evaluation date. Later effective-from dates win; on a tie the most recently
+// registered version wins. Every selected rule set is evaluated and the
+// results are combined under the composition policy.
 type DefaultEngine struct {
-	rules map[string]Rule
+	mu     sync.RWMutex
+	rules  []Rule
+	index  map[string]int // Name_Version -> position in rules
+	policy CompositionPolicy
 }
 
-func NewEngine() Engine {
+func NewEngine() *DefaultEngine {
 	return &DefaultEngine{
-		rules: make(map[string]Rule),
+		index:  make(map[string]int),
+		policy: PolicyStrictest,
 	}
 }
 
+// SetPolicy changes how the results of several applicable rule sets combine
+func (e *DefaultEngine) SetPolicy(policy CompositionPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.policy = policy
+}
+
+// RegisterRule adds a rule version; registering the same name and version
+// again replaces it
 func (e *DefaultEngine) RegisterRule(rule Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	key := fmt.Sprintf("%s_%s", rule.Name(), rule.Version())
-	e.rules[key] = rule
+	if i, exists := e.index[key]; exists {
+		e.rules[i] = rule
+		return
+	}
+	e.index[key] = len(e.rules)
+	e.rules = append(e.rules, rule)
 }
 
 func (e *DefaultEngine) Evaluate(ctx context.Context, etf domain.ETF, country, accountType string) domain.EligibilityResult {
-	// Find applicable rule
-	for _, rule := range e.rules {
-		if rule.AppliesTo(country, accountType) {
-			return rule.Evaluate(ctx, etf)
+	return e.EvaluateAsOf(ctx, etf, country, accountType, time.Now())
+}
+
+func (e *DefaultEngine) EvaluateAsOf(ctx context.Context, etf domain.ETF, country, accountType string, asOf time.Time) domain.EligibilityResult {
+	platform := PlatformFrom(ctx)
+	selected, overlays, applicable := e.rulesInEffect(country, accountType, platform, asOf)
+
+	result := e.statutoryResult(ctx, etf, selected, applicable, accountType, asOf)
+	result.AsOf = asOf
+	if platform != "" {
+		applyOverlays(ctx, &result, etf, platform, overlays)
+	}
+	if len(selected) > 0 {
+		applyDataConflicts(&result, etf)
+	}
+	result.EvidenceCompleteness = evidenceCompleteness(result)
+	return result
+}
+
+// statutoryResult evaluates the rule sets that say what the account may hold
+func (e *DefaultEngine) statutoryResult(ctx context.Context, etf domain.ETF, selected []Rule, applicable bool, accountType string, asOf time.Time) domain.EligibilityResult {
+	if len(selected) > 0 {
+		e.mu.RLock()
+		policy := e.policy
+		e.mu.RUnlock()
+
+		if policy == PolicyFirstMatch {
+			selected = selected[:1]
+		}
+		results := make([]domain.EligibilityResult, 0, len(selected))
+		for _, rule := range selected {
+			results = append(results, rule.Evaluate(ctx, etf))
+		}
+		return compose(policy, selected, results)
+	}
+
+	// Rules exist for this account but none covers the date
+	if applicable {
+		return domain.EligibilityResult{
+			Status:      domain.StatusUnknown,
+			IsEligible:  false,
+			Confidence:  domain.ConfidenceNone,
+			Reasons:     []string{fmt.Sprintf("No eligibility rules in effect on %s for this country/account type combination", asOf.UTC().Format("2006-01-02"))},
+			RulesPassed: []string{},
+			RulesFailed: []string{},
 		}
 	}
 
@@ -53,3 +124,190 @@ func (e *DefaultEngine) Evaluate(ctx context.Context, etf domain.ETF, country, a
 		RulesFailed: []string{},
 	}
 }
+
+// applyOverlays narrows the result to what the investor's platform offers.
+// An eligible or conditional ETF missing from the platform's list becomes
+// not offered; other statuses are kept, with the platform evidence added.
+func applyOverlays(ctx context.Context, result *domain.EligibilityResult, etf domain.ETF, platform string, overlays []Rule) {
+	if len(overlays) == 0 {
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("⚠ No instrument list for platform %s - check that it offers this ETF in your account", platform))
+		return
+	}
+
+	for _, overlay := range overlays {
+		outcome := overlay.Evaluate(ctx, etf)
+		name := overlay.Name()
+
+		if result.RuleVersion == "" {
+			result.RuleVersion = outcome.RuleVersion
+		} else {
+			result.RuleVersion += "+" + outcome.RuleVersion
+		}
+		result.RuleResults = append(result.RuleResults, domain.RuleOutcome{
+			Rule:       name,
+			Version:    outcome.RuleVersion,
+			Status:     outcome.Status,
+			Confidence: outcome.Confidence,
+		})
+		for _, criterion := range outcome.RulesPassed {
+			result.RulesPassed = append(result.RulesPassed, name+"."+criterion)
+		}
+		for _, criterion := range outcome.RulesFailed {
+			result.RulesFailed = append(result.RulesFailed, name+"."+criterion)
+		}
+		for _, criterion := range outcome.RulesSkipped {
+			result.RulesSkipped = append(result.RulesSkipped, name+"."+criterion)
+		}
+		for _, evidence := range outcome.Evidence {
+			evidence.Rule = name
+			result.Evidence = append(result.Evidence, evidence)
+		}
+		result.Reasons = append(result.Reasons, outcome.Reasons...)
+		if domain.ConfidenceRank[outcome.Confidence] < domain.ConfidenceRank[result.Confidence] {
+			result.Confidence = outcome.Confidence
+		}
+
+		if outcome.Status == domain.StatusNotOffered &&
+			(result.Status == domain.StatusEligible || result.Status == domain.StatusConditional) {
+			result.Status = domain.StatusNotOffered
+			result.IsEligible = false
+			result.Reasons = append(result.Reasons,
+				"⚠ Eligible for this account type, but you would need a platform that offers it")
+		}
+	}
+}
+
+// rulesInEffect returns one version per applicable rule name, in registration
+// order, split into statutory rule sets and the overlays for platform.
+// applicable reports whether any statutory version covers the account at all.
+func (e *DefaultEngine) rulesInEffect(country, accountType, platform string, asOf time.Time) (selected, overlays []Rule, applicable bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	chosen := make(map[string]int) // Rule name -> position of the selected version
+	var order []string
+
+	for i, rule := range e.rules {
+		if !rule.AppliesTo(country, accountType) {
+			continue
+		}
+		overlay, isOverlay := rule.(Overlay)
+		if isOverlay && !strings.EqualFold(overlay.Platform(), platform) {
+			continue
+		}
+		if !isOverlay {
+			applicable = true
+		}
+		if !inEffect(rule, asOf) {
+			continue
+		}
+
+		current, seen := chosen[rule.Name()]
+		if !seen {
+			order = append(order, rule.Name())
+		}
+		// Rules are visited in registration order, so a tie goes to the later one
+		if !seen || !effectiveFrom(rule).Before(effectiveFrom(e.rules[current])) {
+			chosen[rule.Name()] = i
+		}
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return chosen[order[a]] < chosen[order[b]]
+	})
+	for _, name := range order {
+		rule := e.rules[chosen[name]]
+		if _, isOverlay := rule.(Overlay); isOverlay {
+			overlays = append(overlays, rule)
+		} else {
+			selected = append(selected, rule)
+		}
+	}
+	return selected, overlays, applicable
+}
+
+// inEffect reports whether asOf falls within the rule's effective period
+func inEffect(rule Rule, asOf time.Time) bool {
+	dated, ok := rule.(EffectivePeriod)
+	if !ok {
+		return true
+	}
+
+	from, to := dated.EffectivePeriod()
+	day := asOf.UTC().Format("2006-01-02")
+	if !from.IsZero() && day < from.UTC().Format("2006-01-02") {
+		return false
+	}
+	if !to.IsZero() && day > to.UTC().Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+func effectiveFrom(rule Rule) time.Time {
+	if dated, ok := rule.(EffectivePeriod); ok {
+		from, _ := dated.EffectivePeriod()
+		return from
+	}
+	return time.Time{}
+}
+
+// evidenceCompleteness scores each criterion out of one: half for evidence
+// with a pass or fail result, half for evidence that cites a data source.
+// Criteria without evidence score nothing.
+func evidenceCompleteness(result domain.EligibilityResult) float64 {
+	criteria := len(result.RulesPassed) + len(result.RulesFailed) + len(result.RulesSkipped)
+	if criteria == 0 {
+		return 0
+	}
+
+	score := 0.0
+	for _, evidence := range result.Evidence {
+		if evidence.Result == "pass" || evidence.Result == "fail" {
+			score += 0.5
+		}
+		if evidence.DataSource.Type != "" || evidence.DataSource.Provider != "" {
+			score += 0.5
+		}
+	}
+	return math.Round(min(score/float64(criteria), 1)*1000) / 10
+}
+
+// ruleInputFields are the ETF fields eligibility rules depend on. Conflicting
+// source data on any of them makes the determination less certain.
+var ruleInputFields = map[string]bool{
+	"exchange":        true,
+	"exchangeCountry": true,
+	"currency":        true,
+	"provider":        true,
+	"domicile":        true,
+	"legalStructure":  true,
+	"isLeveraged":     true,
+	"isInverse":       true,
+	"isSynthetic":     true,
+}
+
+// applyDataConflicts lowers confidence one level for each conflicting rule input
+func applyDataConflicts(result *domain.EligibilityResult, etf domain.ETF) {
+	for _, conflict := range etf.DataConflicts {
+		if !ruleInputFields[conflict.Field] {
+			continue
+		}
+
+		result.Confidence = lowerConfidence(result.Confidence)
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("⚠ Data sources disagree on %s - verify before relying on this result", conflict.Field))
+	}
+}
+
+func lowerConfidence(level domain.ConfidenceLevel) domain.ConfidenceLevel {
+	switch level {
+	case domain.ConfidenceHigh:
+		return domain.ConfidenceMedium
+	case domain.ConfidenceMedium:
+		return domain.ConfidenceLow
+	default:
+		return domain.ConfidenceNone
+	}
+}
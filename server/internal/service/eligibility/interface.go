@@ -2,12 +2,16 @@ package eligibility
 
 import (
 	"context"
+	"time"
+
 	"upstonk/internal/domain"
 )
 
 // Engine evaluates ETF eligibility based on rules
 type Engine interface {
 	Evaluate(ctx context.Context, etf domain.ETF, country, accountType string) domain.EligibilityResult
+	// EvaluateAsOf applies the rule versions that were in effect on asOf
+	EvaluateAsOf(ctx context.Context, etf domain.ETF, country, accountType string, asOf time.Time) domain.EligibilityResult
 	RegisterRule(rule Rule)
 }
 
@@ -18,3 +22,32 @@ type Rule interface {
 	AppliesTo(country, accountType string) bool
 	Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult
 }
+
+// EffectivePeriod is implemented by rule versions that apply only between two
+// dates (inclusive, compared as UTC calendar days). A zero time leaves that
+// end open. Rules without it are always in effect.
+type EffectivePeriod interface {
+	EffectivePeriod() (from, to time.Time)
+}
+
+// Overlay is implemented by rule sets that describe what one investment
+// platform offers rather than what the law allows. An overlay is selected
+// only when the evaluation's platform matches, and it can narrow an eligible
+// result to not offered but never make an ineligible ETF eligible.
+type Overlay interface {
+	Platform() string
+}
+
+type platformKey struct{}
+
+// WithPlatform returns a context that evaluates eligibility for the given
+// investment platform
+func WithPlatform(ctx context.Context, platform string) context.Context {
+	return context.WithValue(ctx, platformKey{}, platform)
+}
+
+// PlatformFrom returns the platform attached to ctx, or ""
+func PlatformFrom(ctx context.Context) string {
+	platform, _ := ctx.Value(platformKey{}).(string)
+	return platform
+}
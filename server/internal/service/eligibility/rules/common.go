@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"strings"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// newResult starts an evaluation that is eligible until a criterion says otherwise
+func newResult(version string) domain.EligibilityResult {
+	return domain.EligibilityResult{
+		RuleVersion:  version,
+		EvaluatedAt:  time.Now(),
+		Status:       domain.StatusEligible,
+		IsEligible:   true,
+		Confidence:   domain.ConfidenceHigh,
+		RulesPassed:  []string{},
+		RulesFailed:  []string{},
+		RulesSkipped: []string{},
+		Evidence:     []domain.EligibilityEvidence{},
+		Reasons:      []string{},
+	}
+}
+
+// criterion records the outcome of one check together with its evidence
+type criterion struct {
+	name     string
+	expected string
+	actual   string
+	source   domain.DataSource
+}
+
+func (c criterion) pass(result *domain.EligibilityResult, reason string) {
+	result.RulesPassed = append(result.RulesPassed, c.name)
+	result.Reasons = append(result.Reasons, "✓ "+reason)
+	result.Evidence = append(result.Evidence, c.evidence("pass"))
+}
+
+func (c criterion) fail(result *domain.EligibilityResult, reason string) {
+	result.RulesFailed = append(result.RulesFailed, c.name)
+	result.Reasons = append(result.Reasons, "✗ "+reason)
+	result.IsEligible = false
+	result.Evidence = append(result.Evidence, c.evidence("fail"))
+}
+
+// unknown records a criterion that could not be verified and caps confidence
+func (c criterion) unknown(result *domain.EligibilityResult, reason string, confidence domain.ConfidenceLevel) {
+	result.RulesSkipped = append(result.RulesSkipped, c.name)
+	result.Reasons = append(result.Reasons, "⚠ "+reason)
+	result.Evidence = append(result.Evidence, c.evidence("unknown"))
+	capConfidence(result, confidence)
+}
+
+func (c criterion) evidence(outcome string) domain.EligibilityEvidence {
+	return domain.EligibilityEvidence{
+		Criterion:  c.name,
+		Expected:   c.expected,
+		Actual:     c.actual,
+		Result:     outcome,
+		DataSource: c.source,
+	}
+}
+
+// fieldSource returns the source that supplied an ETF field, falling back to
+// the ETF's first data source
+func fieldSource(etf domain.ETF, field string) domain.DataSource {
+	if source, ok := etf.FieldProvenance[field]; ok {
+		return source
+	}
+	if len(etf.DataSources) > 0 {
+		return etf.DataSources[0]
+	}
+	return domain.DataSource{}
+}
+
+// typedSource returns the first data source of sourceType, falling back to the
+// source of field
+func typedSource(etf domain.ETF, sourceType, field string) domain.DataSource {
+	for _, ds := range etf.DataSources {
+		if ds.Type == sourceType {
+			return ds
+		}
+	}
+	return fieldSource(etf, field)
+}
+
+// capConfidence lowers confidence to level if it is currently higher
+func capConfidence(result *domain.EligibilityResult, level domain.ConfidenceLevel) {
+	if domain.ConfidenceRank[result.Confidence] > domain.ConfidenceRank[level] {
+		result.Confidence = level
+	}
+}
+
+// finalization sets how recorded criteria become a status
+type finalization struct {
+	maxSkipped        int
+	unknownReason     string
+	conditionalReason string
+}
+
+// finalize derives the status from the recorded criteria, recommending
+// verification with verifyWith when the result is unknown
+func finalize(result *domain.EligibilityResult, maxSkipped int, verifyWith string) {
+	finalizeWith(result, finalization{
+		maxSkipped:    maxSkipped,
+		unknownReason: "Insufficient data to confirm eligibility - recommend verification with " + verifyWith,
+	})
+}
+
+// finalizeWith applies the status policy: any failure is ineligible, more than
+// maxSkipped unverified criteria or low confidence is unknown, and any
+// unverified criterion makes the result conditional
+func finalizeWith(result *domain.EligibilityResult, policy finalization) {
+	if policy.unknownReason == "" {
+		policy.unknownReason = "Insufficient data to confirm eligibility - recommend independent verification"
+	}
+	if policy.conditionalReason == "" {
+		policy.conditionalReason = "Likely eligible but verification recommended before investing"
+	}
+
+	switch {
+	case len(result.RulesFailed) > 0:
+		result.Status = domain.StatusIneligible
+		result.IsEligible = false
+	case len(result.RulesSkipped) > policy.maxSkipped || domain.ConfidenceRank[result.Confidence] <= domain.ConfidenceRank[domain.ConfidenceLow]:
+		result.Status = domain.StatusUnknown
+		result.IsEligible = false
+		result.Reasons = append(result.Reasons, "⚠ "+policy.unknownReason)
+	case len(result.RulesSkipped) > 0:
+		result.Status = domain.StatusConditional
+		result.IsEligible = true
+		result.Reasons = append(result.Reasons, "✓ "+policy.conditionalReason)
+		capConfidence(result, domain.ConfidenceMedium)
+	default:
+		result.Status = domain.StatusEligible
+		result.IsEligible = true
+	}
+}
+
+func normalize(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
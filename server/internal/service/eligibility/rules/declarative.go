@@ -0,0 +1,558 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// DeclarativeRule is a rule set described in a JSON file rather than Go code,
+// so compliance staff can ship rule changes as data
+type DeclarativeRule struct {
+	spec DeclarativeSpec
+	path string
+	from time.Time
+	to   time.Time
+}
+
+// DeclarativeSpec is the file format of a declarative rule set
+type DeclarativeSpec struct {
+	Name          string          `json:"name"`
+	Version       string          `json:"version"`
+	Description   string          `json:"description,omitempty"`
+	Reference     string          `json:"reference,omitempty"`
+	EffectiveFrom string          `json:"effectiveFrom,omitempty"` // First day this version applies (YYYY-MM-DD); empty is open
+	EffectiveTo   string          `json:"effectiveTo,omitempty"`   // Last day this version applies; empty is open
+	AppliesTo     []Applicability `json:"appliesTo"`
+	Criteria      []CriterionSpec `json:"criteria"`
+	Finalize      FinalizeSpec    `json:"finalize"`
+}
+
+// Applicability selects the country and account types a rule set covers
+type Applicability struct {
+	Country      string   `json:"country"`
+	AccountTypes []string `json:"accountTypes"`
+}
+
+// CriterionSpec is one check. Outcomes are tried in order and the first whose
+// condition holds is recorded; if none holds the criterion is not recorded.
+// Expected, Actual and reasons may reference ETF fields as {field.path}.
+type CriterionSpec struct {
+	ID          string        `json:"id"`
+	Expected    string        `json:"expected"`
+	Actual      string        `json:"actual"`
+	SourceField string        `json:"sourceField,omitempty"` // Field whose provenance is cited as evidence
+	SourceType  string        `json:"sourceType,omitempty"`  // Or: cite the first DataSource of this type
+	Outcomes    []OutcomeSpec `json:"outcomes"`
+}
+
+// OutcomeSpec records a criterion result when its condition holds (always, if When is nil)
+type OutcomeSpec struct {
+	When       *Condition `json:"when,omitempty"`
+	Result     string     `json:"result"`               // "pass", "fail" or "unknown"
+	Confidence string     `json:"confidence,omitempty"` // Confidence cap for "unknown"; default medium
+	Reason     string     `json:"reason"`
+	Assumed    bool       `json:"assumed,omitempty"` // Result is assumed, not read from data: its evidence cites no source
+}
+
+// Condition is a predicate over ETF fields or earlier criterion results.
+// Exactly one of All, Any, Not, Field or Criterion is set.
+//
+// Field operators: equals, notEquals, in, notIn, contains, containsAny,
+// empty, notEmpty, true, false, gt, gte, lt, lte.
+// Criterion operators: passed, failed, skipped.
+type Condition struct {
+	All       []Condition `json:"all,omitempty"`
+	Any       []Condition `json:"any,omitempty"`
+	Not       *Condition  `json:"not,omitempty"`
+	Field     string      `json:"field,omitempty"`
+	Criterion string      `json:"criterion,omitempty"`
+	Op        string      `json:"op,omitempty"`
+	Value     string      `json:"value,omitempty"`
+	Values    []string    `json:"values,omitempty"`
+	Number    *float64    `json:"number,omitempty"`
+}
+
+// FinalizeSpec sets how criterion results become a status
+type FinalizeSpec struct {
+	MaxSkipped        int    `json:"maxSkipped"`                  // More unverified criteria than this is unknown
+	UnknownReason     string `json:"unknownReason,omitempty"`     // Appended when the result is unknown
+	ConditionalReason string `json:"conditionalReason,omitempty"` // Appended when the result is conditional
+}
+
+// LoadDeclarativeRule reads and validates a rule file
+func LoadDeclarativeRule(path string) (*DeclarativeRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rule file: %w", err)
+	}
+
+	rule, err := ParseDeclarativeRule(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rule.path = path
+	return rule, nil
+}
+
+// LoadDeclarativeRules loads every *.json file in dir. Invalid files are
+// skipped and reported in the returned error alongside the valid rules.
+func LoadDeclarativeRules(dir string) ([]*DeclarativeRule, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list rule files: %w", err)
+	}
+	sort.Strings(paths)
+
+	var loaded []*DeclarativeRule
+	var errs []error
+	for _, path := range paths {
+		rule, err := LoadDeclarativeRule(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, rule)
+	}
+	return loaded, errors.Join(errs...)
+}
+
+// ParseDeclarativeRule decodes and validates a rule definition
+func ParseDeclarativeRule(data []byte) (*DeclarativeRule, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+
+	var spec DeclarativeSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("decode rule: %w", err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	rule := &DeclarativeRule{spec: spec}
+	var err error
+	if rule.from, err = parseEffectiveDate(spec.EffectiveFrom); err != nil {
+		return nil, fmt.Errorf("effectiveFrom: %w", err)
+	}
+	if rule.to, err = parseEffectiveDate(spec.EffectiveTo); err != nil {
+		return nil, fmt.Errorf("effectiveTo: %w", err)
+	}
+	if !rule.from.IsZero() && !rule.to.IsZero() && rule.to.Before(rule.from) {
+		return nil, errors.New("effectiveTo is before effectiveFrom")
+	}
+	return rule, nil
+}
+
+func parseEffectiveDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func (r *DeclarativeRule) Name() string {
+	return r.spec.Name
+}
+
+func (r *DeclarativeRule) Version() string {
+	return r.spec.Version
+}
+
+// EffectivePeriod returns the dates this version applies between (zero when open)
+func (r *DeclarativeRule) EffectivePeriod() (from, to time.Time) {
+	return r.from, r.to
+}
+
+// Path is the file the rule was loaded from, if any
+func (r *DeclarativeRule) Path() string {
+	return r.path
+}
+
+func (r *DeclarativeRule) AppliesTo(country, accountType string) bool {
+	for _, scope := range r.spec.AppliesTo {
+		if !strings.EqualFold(scope.Country, country) {
+			continue
+		}
+		for _, candidate := range scope.AccountTypes {
+			if strings.EqualFold(candidate, accountType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Evaluate interprets the criteria against the ETF
+func (r *DeclarativeRule) Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult {
+	result := newResult(r.spec.Version)
+	outcomes := make(map[string]string, len(r.spec.Criteria))
+
+	for _, spec := range r.spec.Criteria {
+		outcome, ok := spec.match(etf, outcomes)
+		if !ok {
+			continue
+		}
+
+		c := criterion{
+			name:     spec.ID,
+			expected: render(spec.Expected, etf),
+			actual:   render(spec.Actual, etf),
+			source:   spec.source(etf),
+		}
+		if outcome.Assumed {
+			c.source = domain.DataSource{}
+		}
+		reason := render(outcome.Reason, etf)
+
+		switch outcome.Result {
+		case "pass":
+			c.pass(&result, reason)
+		case "fail":
+			c.fail(&result, reason)
+		case "unknown":
+			c.unknown(&result, reason, outcome.confidence())
+		}
+		outcomes[spec.ID] = outcome.Result
+	}
+
+	finalizeWith(&result, finalization{
+		maxSkipped:        r.spec.Finalize.MaxSkipped,
+		unknownReason:     r.spec.Finalize.UnknownReason,
+		conditionalReason: r.spec.Finalize.ConditionalReason,
+	})
+
+	return result
+}
+
+func (c CriterionSpec) match(etf domain.ETF, outcomes map[string]string) (OutcomeSpec, bool) {
+	for _, outcome := range c.Outcomes {
+		if outcome.When == nil || outcome.When.holds(etf, outcomes) {
+			return outcome, true
+		}
+	}
+	return OutcomeSpec{}, false
+}
+
+func (c CriterionSpec) source(etf domain.ETF) domain.DataSource {
+	if c.SourceType != "" {
+		return typedSource(etf, c.SourceType, c.SourceField)
+	}
+	return fieldSource(etf, c.SourceField)
+}
+
+func (o OutcomeSpec) confidence() domain.ConfidenceLevel {
+	if o.Confidence == "" {
+		return domain.ConfidenceMedium
+	}
+	return domain.ConfidenceLevel(o.Confidence)
+}
+
+func (c Condition) holds(etf domain.ETF, outcomes map[string]string) bool {
+	switch {
+	case len(c.All) > 0:
+		for _, sub := range c.All {
+			if !sub.holds(etf, outcomes) {
+				return false
+			}
+		}
+		return true
+	case len(c.Any) > 0:
+		for _, sub := range c.Any {
+			if sub.holds(etf, outcomes) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.holds(etf, outcomes)
+	case c.Criterion != "":
+		return outcomes[c.Criterion] == criterionOps[c.Op]
+	}
+
+	value, _ := lookupField(etf, c.Field)
+	text := strings.ToUpper(strings.TrimSpace(formatValue(value)))
+
+	switch c.Op {
+	case "equals":
+		return text == strings.ToUpper(c.Value)
+	case "notEquals":
+		return text != strings.ToUpper(c.Value)
+	case "in":
+		return containsFold(c.Values, text)
+	case "notIn":
+		return !containsFold(c.Values, text)
+	case "contains":
+		return strings.Contains(text, strings.ToUpper(c.Value))
+	case "containsAny":
+		for _, candidate := range c.Values {
+			if strings.Contains(text, strings.ToUpper(candidate)) {
+				return true
+			}
+		}
+		return false
+	case "empty":
+		return !value.IsValid() || value.IsZero()
+	case "notEmpty":
+		return value.IsValid() && !value.IsZero()
+	case "true":
+		return value.Kind() == reflect.Bool && value.Bool()
+	case "false":
+		return value.Kind() == reflect.Bool && !value.Bool()
+	case "gt", "gte", "lt", "lte":
+		number, ok := numeric(value)
+		if !ok {
+			return false
+		}
+		switch c.Op {
+		case "gt":
+			return number > *c.Number
+		case "gte":
+			return number >= *c.Number
+		case "lt":
+			return number < *c.Number
+		default:
+			return number <= *c.Number
+		}
+	}
+	return false
+}
+
+// criterionOps maps criterion operators to the outcome they test for
+var criterionOps = map[string]string{
+	"passed":  "pass",
+	"failed":  "fail",
+	"skipped": "unknown",
+}
+
+var fieldOps = map[string]bool{
+	"equals": true, "notEquals": true, "in": true, "notIn": true,
+	"contains": true, "containsAny": true, "empty": true, "notEmpty": true,
+	"true": true, "false": true, "gt": true, "gte": true, "lt": true, "lte": true,
+}
+
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_.]+)\}`)
+
+// render substitutes {field.path} placeholders with the ETF's values
+func render(template string, etf domain.ETF) string {
+	return placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		value, ok := lookupField(etf, match[1:len(match)-1])
+		if !ok {
+			return match
+		}
+		return formatValue(value)
+	})
+}
+
+// lookupField resolves a dotted path of JSON field names (e.g. "assetExposure.equities"
+// or "geographicExposure.countries.ZA") on the ETF. ok is false for an invalid path.
+func lookupField(etf domain.ETF, path string) (reflect.Value, bool) {
+	value := reflect.ValueOf(etf)
+	for _, part := range strings.Split(path, ".") {
+		switch value.Kind() {
+		case reflect.Struct:
+			field, ok := jsonField(value, part)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			value = field
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			entry := value.MapIndex(reflect.ValueOf(part).Convert(value.Type().Key()))
+			if !entry.IsValid() {
+				entry = reflect.Zero(value.Type().Elem())
+			}
+			value = entry
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return value, true
+}
+
+func jsonField(value reflect.Value, name string) (reflect.Value, bool) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if strings.EqualFold(tag, name) {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func formatValue(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+	switch v := value.Interface().(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+func numeric(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	}
+	return 0, false
+}
+
+func containsFold(values []string, upper string) bool {
+	for _, candidate := range values {
+		if strings.ToUpper(strings.TrimSpace(candidate)) == upper {
+			return true
+		}
+	}
+	return false
+}
+
+// validate rejects definitions the interpreter could not evaluate faithfully
+func (s DeclarativeSpec) validate() error {
+	if s.Name == "" || s.Version == "" {
+		return errors.New("rule name and version are required")
+	}
+	if len(s.AppliesTo) == 0 {
+		return errors.New("appliesTo must list at least one country")
+	}
+	for _, scope := range s.AppliesTo {
+		if scope.Country == "" || len(scope.AccountTypes) == 0 {
+			return errors.New("appliesTo entries need a country and account types")
+		}
+	}
+	if len(s.Criteria) == 0 {
+		return errors.New("at least one criterion is required")
+	}
+	if s.Finalize.MaxSkipped < 0 {
+		return errors.New("finalize.maxSkipped must not be negative")
+	}
+
+	defined := make(map[string]bool, len(s.Criteria))
+	for _, c := range s.Criteria {
+		if c.ID == "" {
+			return errors.New("criterion id is required")
+		}
+		if defined[c.ID] {
+			return fmt.Errorf("criterion %s: duplicate id", c.ID)
+		}
+		if len(c.Outcomes) == 0 {
+			return fmt.Errorf("criterion %s: at least one outcome is required", c.ID)
+		}
+		if c.SourceField != "" {
+			if _, ok := lookupField(domain.ETF{}, c.SourceField); !ok {
+				return fmt.Errorf("criterion %s: unknown source field %q", c.ID, c.SourceField)
+			}
+		}
+		templates := []string{c.Expected, c.Actual}
+		for i, outcome := range c.Outcomes {
+			switch outcome.Result {
+			case "pass", "fail", "unknown":
+			default:
+				return fmt.Errorf("criterion %s outcome %d: result must be pass, fail or unknown", c.ID, i+1)
+			}
+			if _, ok := domain.ConfidenceRank[domain.ConfidenceLevel(outcome.Confidence)]; outcome.Confidence != "" && !ok {
+				return fmt.Errorf("criterion %s outcome %d: invalid confidence %q", c.ID, i+1, outcome.Confidence)
+			}
+			if outcome.When != nil {
+				if err := outcome.When.validate(defined); err != nil {
+					return fmt.Errorf("criterion %s outcome %d: %w", c.ID, i+1, err)
+				}
+			}
+			templates = append(templates, outcome.Reason)
+		}
+		for _, template := range templates {
+			for _, match := range placeholder.FindAllStringSubmatch(template, -1) {
+				if _, ok := lookupField(domain.ETF{}, match[1]); !ok {
+					return fmt.Errorf("criterion %s: unknown field {%s} in template", c.ID, match[1])
+				}
+			}
+		}
+		defined[c.ID] = true
+	}
+	return nil
+}
+
+func (c Condition) validate(defined map[string]bool) error {
+	set := 0
+	for _, present := range []bool{len(c.All) > 0, len(c.Any) > 0, c.Not != nil, c.Field != "", c.Criterion != ""} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("condition must set exactly one of all, any, not, field or criterion")
+	}
+
+	for _, sub := range append(append([]Condition{}, c.All...), c.Any...) {
+		if err := sub.validate(defined); err != nil {
+			return err
+		}
+	}
+	if c.Not != nil {
+		return c.Not.validate(defined)
+	}
+
+	if c.Criterion != "" {
+		if !defined[c.Criterion] {
+			return fmt.Errorf("criterion %q must be defined before it is referenced", c.Criterion)
+		}
+		if _, ok := criterionOps[c.Op]; !ok {
+			return fmt.Errorf("criterion condition op must be passed, failed or skipped, got %q", c.Op)
+		}
+		return nil
+	}
+
+	if c.Field != "" {
+		value, ok := lookupField(domain.ETF{}, c.Field)
+		if !ok {
+			return fmt.Errorf("unknown field %q", c.Field)
+		}
+		if !fieldOps[c.Op] {
+			return fmt.Errorf("unknown op %q on field %s", c.Op, c.Field)
+		}
+		switch c.Op {
+		case "gt", "gte", "lt", "lte":
+			if c.Number == nil {
+				return fmt.Errorf("op %s on field %s needs a number", c.Op, c.Field)
+			}
+			if _, ok := numeric(value); !ok {
+				return fmt.Errorf("op %s needs a numeric field, %s is not", c.Op, c.Field)
+			}
+		case "true", "false":
+			if value.Kind() != reflect.Bool {
+				return fmt.Errorf("op %s needs a boolean field, %s is not", c.Op, c.Field)
+			}
+		case "in", "notIn", "containsAny":
+			if len(c.Values) == 0 {
+				return fmt.Errorf("op %s on field %s needs values", c.Op, c.Field)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// IRAUSRules implements eligibility rules for US retirement accounts:
+// Traditional IRA, Roth IRA and 401(k)
+// Based on: Internal Revenue Code §408, §408A and §401(k); PFIC rules in §1291-1298
+// The tax code permits most securities, so these rules flag holdings custodians
+// and plan sponsors commonly block or that carry foreign-fund tax complications.
+type IRAUSRules struct {
+	version     string
+	name        string
+	accountType string
+	label       string
+}
+
+func NewIRAUSRules() *IRAUSRules {
+	return &IRAUSRules{
+		version:     "ira_us_v1.0_2025",
+		name:        "IRA_US",
+		accountType: "ira",
+		label:       "IRA",
+	}
+}
+
+func NewRothIRAUSRules() *IRAUSRules {
+	return &IRAUSRules{
+		version:     "roth_ira_us_v1.0_2025",
+		name:        "ROTH_IRA_US",
+		accountType: "roth_ira",
+		label:       "Roth IRA",
+	}
+}
+
+func New401kUSRules() *IRAUSRules {
+	return &IRAUSRules{
+		version:     "401k_us_v1.0_2025",
+		name:        "401K_US",
+		accountType: "401k",
+		label:       "401(k)",
+	}
+}
+
+func (r *IRAUSRules) Name() string {
+	return r.name
+}
+
+func (r *IRAUSRules) Version() string {
+	return r.version
+}
+
+func (r *IRAUSRules) AppliesTo(country, accountType string) bool {
+	return strings.ToUpper(country) == "US" && strings.ToLower(accountType) == r.accountType
+}
+
+// usExchange reports whether an exchange code or name, as reported by data
+// providers, is a US national securities exchange
+func usExchange(exchange string) bool {
+	return domain.ExchangeCountry(exchange) == "US"
+}
+
+// Evaluate checks whether the ETF is a holding US retirement custodians support
+func (r *IRAUSRules) Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult {
+	result := newResult(r.version)
+
+	// Rule 1: Must be listed on a US exchange
+	r.checkUSListing(&result, etf)
+
+	// Rule 2: Foreign-domiciled funds are PFICs
+	r.checkPFIC(&result, etf)
+
+	// Rule 3: Leveraged and inverse products are commonly restricted
+	r.checkStructure(&result, etf)
+
+	// Rule 4: 401(k) plans only hold what the plan offers
+	if r.accountType == "401k" {
+		r.checkPlanMenu(&result, etf)
+	}
+
+	finalize(&result, 2, "your "+r.label+" custodian")
+
+	return result
+}
+
+func (r *IRAUSRules) checkUSListing(result *domain.EligibilityResult, etf domain.ETF) {
+	c := criterion{
+		name:     "us_listing",
+		expected: "Listed on a US national securities exchange",
+		actual:   fmt.Sprintf("Exchange: %s, Country: %s", etf.Exchange, etf.ExchangeCountry),
+		source:   fieldSource(etf, "exchange"),
+	}
+
+	exchange := normalize(etf.Exchange)
+	country := normalize(etf.ExchangeCountry)
+
+	switch {
+	case usExchange(exchange):
+		c.pass(result, fmt.Sprintf("Listed on US exchange: %s", etf.Exchange))
+	case country == "US":
+		c.unknown(result, fmt.Sprintf("US listing reported but exchange '%s' not recognised - verify", etf.Exchange),
+			domain.ConfidenceMedium)
+	case exchange == "" && (country == "" || country == "UNKNOWN"):
+		c.unknown(result, "Listing exchange not identified - verification required", domain.ConfidenceLow)
+	default:
+		c.fail(result, fmt.Sprintf("Listed on %s - most %s custodians cannot hold foreign-listed funds", etf.Exchange, r.label))
+	}
+}
+
+func (r *IRAUSRules) checkPFIC(result *domain.EligibilityResult, etf domain.ETF) {
+	c := criterion{
+		name:     "pfic_exposure",
+		expected: "US-domiciled fund (not a PFIC)",
+		actual:   fmt.Sprintf("Domicile: %s", etf.Domicile),
+		source:   fieldSource(etf, "domicile"),
+	}
+
+	switch domicile := normalize(etf.Domicile); {
+	case domicile == "US":
+		c.pass(result, "US-domiciled fund - no PFIC exposure")
+	case domicile == "" && usExchange(etf.Exchange):
+		c.unknown(result, "Domicile not reported - US-listed ETFs are normally US-domiciled", domain.ConfidenceMedium)
+	case domicile == "":
+		c.unknown(result, "Domicile unknown - cannot rule out PFIC status", domain.ConfidenceLow)
+	default:
+		c.unknown(result, fmt.Sprintf("Foreign-domiciled fund (%s) is a PFIC - usually exempt from Form 8621 in a retirement account, but many custodians will not hold it", domicile),
+			domain.ConfidenceMedium)
+	}
+}
+
+func (r *IRAUSRules) checkStructure(result *domain.EligibilityResult, etf domain.ETF) {
+	leverage := criterion{
+		name:     "no_leverage",
+		expected: "Non-leveraged ETF",
+		actual:   fmt.Sprintf("Leveraged: %t", etf.IsLeveraged),
+		source:   fieldSource(etf, "isLeveraged"),
+	}
+	r.checkRestrictedProduct(result, leverage, etf.IsLeveraged, "Leveraged", "Not leveraged")
+
+	inverse := criterion{
+		name:     "no_inverse",
+		expected: "Standard tracking ETF",
+		actual:   fmt.Sprintf("Inverse: %t", etf.IsInverse),
+		source:   fieldSource(etf, "isInverse"),
+	}
+	r.checkRestrictedProduct(result, inverse, etf.IsInverse, "Inverse", "Not inverse")
+}
+
+// checkRestrictedProduct fails leveraged/inverse products in 401(k) plans, which
+// exclude them from plan menus, and leaves them conditional in IRAs where
+// custodian policy varies
+func (r *IRAUSRules) checkRestrictedProduct(result *domain.EligibilityResult, c criterion, restricted bool, kind, ok string) {
+	switch {
+	case !restricted:
+		c.pass(result, ok)
+	case r.accountType == "401k":
+		c.fail(result, fmt.Sprintf("%s products are excluded from 401(k) plan menus", kind))
+	default:
+		c.unknown(result, fmt.Sprintf("%s products are blocked or require a margin/options agreement at many %s custodians", kind, r.label),
+			domain.ConfidenceMedium)
+	}
+}
+
+func (r *IRAUSRules) checkPlanMenu(result *domain.EligibilityResult, etf domain.ETF) {
+	c := criterion{
+		name:     "plan_menu",
+		expected: "Offered by the plan or via a self-directed brokerage window",
+		actual:   fmt.Sprintf("Ticker: %s (plan menu not available)", etf.Ticker),
+		source:   fieldSource(etf, "exchange"),
+	}
+	c.unknown(result, "401(k) plans can only hold funds on the plan menu or through a brokerage window - check with your plan administrator",
+		domain.ConfidenceMedium)
+}
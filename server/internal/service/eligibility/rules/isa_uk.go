@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// ISAUKRules implements eligibility rules for UK Stocks & Shares ISAs
+// Based on: The Individual Savings Account Regulations 1998 (SI 1998/1870), reg. 7
+// Reference: https://www.gov.uk/government/publications/recognised-stock-exchanges-definition-legislation-and-tables
+type ISAUKRules struct {
+	version string
+}
+
+func NewISAUKRules() *ISAUKRules {
+	return &ISAUKRules{
+		version: "isa_uk_v1.0_2025",
+	}
+}
+
+func (r *ISAUKRules) Name() string {
+	return "ISA_UK"
+}
+
+func (r *ISAUKRules) Version() string {
+	return r.version
+}
+
+func (r *ISAUKRules) AppliesTo(country, accountType string) bool {
+	return strings.ToUpper(country) == "GB" && strings.ToLower(accountType) == "isa"
+}
+
+// HMRC-designated recognised stock exchanges (subset covering ETF venues),
+// keyed by exchange code or name as reported by data providers
+var recognisedExchanges = map[string]string{
+	"LSE": "GB", "LON": "GB", "LONDON STOCK EXCHANGE": "GB",
+	"NYSE": "US", "NYQ": "US", "NYSEARCA": "US", "PCX": "US", "NASDAQ": "US", "NAS": "US", "NMS": "US", "NGM": "US", "BATS": "US", "CBOE": "US", "AMEX": "US",
+	"XETRA": "DE", "XETR": "DE", "FRA": "DE", "GER": "DE",
+	"EURONEXT": "NL", "AMS": "NL", "PAR": "FR", "EPA": "FR", "BRU": "BE", "LIS": "PT", "DUB": "IE", "ISE": "IE",
+	"SIX": "CH", "SWX": "CH", "EBS": "CH",
+	"BIT": "IT", "MIL": "IT",
+	"JSE": "ZA", "JNB": "ZA", "TSX": "CA", "ASX": "AU", "TSE": "JP", "HKEX": "HK", "HKG": "HK",
+}
+
+// Countries whose main exchange is HMRC-recognised, for listings reported only by country
+var recognisedExchangeCountries = map[string]bool{
+	"GB": true, "US": true, "DE": true, "NL": true, "FR": true, "BE": true, "PT": true, "IE": true,
+	"CH": true, "IT": true, "ZA": true, "CA": true, "AU": true, "JP": true, "HK": true,
+}
+
+// UCITS funds can only be domiciled in the EEA; UK-domiciled funds are UK UCITS
+var ucitsDomiciles = map[string]bool{
+	"IE": true, "LU": true, "GB": true, "FR": true, "DE": true, "NL": true,
+	"AT": true, "BE": true, "DK": true, "ES": true, "FI": true, "IT": true, "SE": true,
+}
+
+// Evaluate performs the Stocks & Shares ISA qualifying-investment check
+func (r *ISAUKRules) Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult {
+	result := newResult(r.version)
+
+	// Rule 1: Must be listed on an HMRC-recognised stock exchange
+	r.checkRecognisedExchange(&result, etf)
+
+	// Rule 2: Must be a UCITS (or UK-authorised) fund that UK retail investors can buy
+	r.checkUCITS(&result, etf)
+
+	// Rule 3: Must not be leveraged or inverse
+	r.checkStructure(&result, etf)
+
+	// Rule 4: Currency is not restricted, but flag FX costs
+	r.checkCurrency(&result, etf)
+
+	finalize(&result, 2, "your ISA provider")
+
+	return result
+}
+
+func (r *ISAUKRules) checkRecognisedExchange(result *domain.EligibilityResult, etf domain.ETF) {
+	c := criterion{
+		name:     "recognised_exchange",
+		expected: "Listed on an HMRC-recognised stock exchange",
+		actual:   fmt.Sprintf("Exchange: %s, Country: %s", etf.Exchange, etf.ExchangeCountry),
+		source:   fieldSource(etf, "exchange"),
+	}
+
+	exchange := normalize(etf.Exchange)
+	country := normalize(etf.ExchangeCountry)
+
+	switch {
+	case recognisedExchanges[exchange] != "":
+		c.pass(result, fmt.Sprintf("Listed on recognised exchange: %s", etf.Exchange))
+	case exchange == "" && recognisedExchangeCountries[country]:
+		c.unknown(result, fmt.Sprintf("Exchange not reported; listing country %s has a recognised exchange", country),
+			domain.ConfidenceMedium)
+	case recognisedExchangeCountries[country]:
+		c.unknown(result, fmt.Sprintf("Exchange '%s' not in known recognised list - verify HMRC designation", etf.Exchange),
+			domain.ConfidenceMedium)
+	case exchange == "" && (country == "" || country == "UNKNOWN"):
+		c.unknown(result, "Listing exchange not identified - verification required", domain.ConfidenceLow)
+	default:
+		c.fail(result, fmt.Sprintf("Exchange '%s' is not an HMRC-recognised stock exchange", etf.Exchange))
+	}
+}
+
+func (r *ISAUKRules) checkUCITS(result *domain.EligibilityResult, etf domain.ETF) {
+	c := criterion{
+		name:     "ucits_qualifying_investment",
+		expected: "UCITS or UK-authorised fund (PRIIPs KID available to UK retail investors)",
+		actual:   fmt.Sprintf("Legal structure: %s, Domicile: %s", etf.LegalStructure, etf.Domicile),
+		source:   fieldSource(etf, "legalStructure"),
+	}
+
+	structure := strings.ToUpper(etf.LegalStructure)
+	domicile := normalize(etf.Domicile)
+
+	switch {
+	case strings.Contains(structure, "UCITS"):
+		c.pass(result, "UCITS fund")
+	case domicile == "GB":
+		c.pass(result, "UK-authorised fund")
+	case ucitsDomiciles[domicile]:
+		c.source = fieldSource(etf, "domicile")
+		c.unknown(result, fmt.Sprintf("Domiciled in %s but UCITS status not confirmed - check the fund's KID", domicile),
+			domain.ConfidenceMedium)
+	case domicile == "":
+		c.unknown(result, "Domicile and legal structure unknown - cannot confirm UCITS status", domain.ConfidenceLow)
+	default:
+		c.source = fieldSource(etf, "domicile")
+		c.fail(result, fmt.Sprintf("Non-UCITS fund domiciled in %s - UK platforms cannot offer it to retail ISA investors without a PRIIPs KID", domicile))
+	}
+}
+
+func (r *ISAUKRules) checkStructure(result *domain.EligibilityResult, etf domain.ETF) {
+	leverage := criterion{
+		name:     "no_leverage",
+		expected: "Non-leveraged ETF",
+		actual:   fmt.Sprintf("Leveraged: %t", etf.IsLeveraged),
+		source:   fieldSource(etf, "isLeveraged"),
+	}
+	if etf.IsLeveraged {
+		leverage.fail(result, "Leveraged products are excluded from ISA recommendations")
+	} else {
+		leverage.pass(result, "Not leveraged")
+	}
+
+	inverse := criterion{
+		name:     "no_inverse",
+		expected: "Standard tracking ETF",
+		actual:   fmt.Sprintf("Inverse: %t", etf.IsInverse),
+		source:   fieldSource(etf, "isInverse"),
+	}
+	if etf.IsInverse {
+		inverse.fail(result, "Inverse products are excluded from ISA recommendations")
+	} else {
+		inverse.pass(result, "Not inverse")
+	}
+}
+
+func (r *ISAUKRules) checkCurrency(result *domain.EligibilityResult, etf domain.ETF) {
+	c := criterion{
+		name:     "currency",
+		expected: "Any currency (ISAs have no currency restriction)",
+		actual:   fmt.Sprintf("Currency: %s", etf.Currency),
+		source:   fieldSource(etf, "currency"),
+	}
+
+	switch currency := normalize(etf.Currency); currency {
+	case "GBP", "GBX":
+		c.pass(result, "Traded in sterling")
+	case "":
+		c.pass(result, "Currency not reported (not an ISA restriction)")
+	default:
+		c.pass(result, fmt.Sprintf("Traded in %s - platform FX charges may apply", currency))
+	}
+}
@@ -0,0 +1,190 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// platformListMaxAge is how old an instrument list can be before results
+// based on it drop to medium confidence
+const platformListMaxAge = 90 * 24 * time.Hour
+
+// PlatformSpec is the JSON form of one platform's instrument list for one country
+type PlatformSpec struct {
+	Platform     string   `json:"platform"` // Identifier investors send, e.g. "easyequities"
+	Name         string   `json:"name"`     // Display name, e.g. "EasyEquities"
+	Version      string   `json:"version"`
+	Source       string   `json:"source,omitempty"` // Where the list was taken from
+	Updated      string   `json:"updated"`          // YYYY-MM-DD the list was last checked
+	Country      string   `json:"country"`
+	AccountTypes []string `json:"accountTypes"`
+	Instruments  []string `json:"instruments"` // Tickers or ISINs offered in these accounts
+}
+
+// PlatformOverlay checks an ETF against the instruments a platform offers in
+// an account type. ETFs missing from the list are reported as not offered.
+type PlatformOverlay struct {
+	spec        PlatformSpec
+	path        string
+	updated     time.Time
+	instruments map[string]bool
+}
+
+// LoadPlatformOverlay reads and validates a platform list file
+func LoadPlatformOverlay(path string) (*PlatformOverlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read platform file: %w", err)
+	}
+
+	overlay, err := ParsePlatformOverlay(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	overlay.path = path
+	return overlay, nil
+}
+
+// LoadPlatformOverlays loads every *.json file in dir. Invalid files are
+// skipped and reported in the returned error alongside the valid overlays.
+func LoadPlatformOverlays(dir string) ([]*PlatformOverlay, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list platform files: %w", err)
+	}
+	sort.Strings(paths)
+
+	var loaded []*PlatformOverlay
+	var errs []error
+	for _, path := range paths {
+		overlay, err := LoadPlatformOverlay(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, overlay)
+	}
+	return loaded, errors.Join(errs...)
+}
+
+// ParsePlatformOverlay decodes and validates a platform list
+func ParsePlatformOverlay(data []byte) (*PlatformOverlay, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+
+	var spec PlatformSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("decode platform list: %w", err)
+	}
+
+	switch {
+	case strings.TrimSpace(spec.Platform) == "":
+		return nil, fmt.Errorf("platform is required")
+	case spec.Version == "":
+		return nil, fmt.Errorf("version is required")
+	case spec.Country == "" || len(spec.AccountTypes) == 0:
+		return nil, fmt.Errorf("country and accountTypes are required")
+	case len(spec.Instruments) == 0:
+		return nil, fmt.Errorf("instruments must not be empty")
+	}
+
+	updated, err := time.Parse("2006-01-02", spec.Updated)
+	if err != nil {
+		return nil, fmt.Errorf("updated must be YYYY-MM-DD: %w", err)
+	}
+	if spec.Name == "" {
+		spec.Name = spec.Platform
+	}
+
+	overlay := &PlatformOverlay{
+		spec:        spec,
+		updated:     updated,
+		instruments: make(map[string]bool, len(spec.Instruments)),
+	}
+	for _, instrument := range spec.Instruments {
+		overlay.instruments[normalize(instrument)] = true
+	}
+	return overlay, nil
+}
+
+func (p *PlatformOverlay) Name() string {
+	return "PLATFORM_" + normalize(p.spec.Platform)
+}
+
+func (p *PlatformOverlay) Version() string {
+	return p.spec.Version
+}
+
+// Platform identifies the platform this list belongs to
+func (p *PlatformOverlay) Platform() string {
+	return p.spec.Platform
+}
+
+// Path is the file the list was loaded from, if any
+func (p *PlatformOverlay) Path() string {
+	return p.path
+}
+
+func (p *PlatformOverlay) AppliesTo(country, accountType string) bool {
+	if !strings.EqualFold(country, p.spec.Country) {
+		return false
+	}
+	for _, supported := range p.spec.AccountTypes {
+		if strings.EqualFold(accountType, supported) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate reports whether the platform offers the ETF. The result is
+// eligible when it does and not offered when it does not.
+func (p *PlatformOverlay) Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult {
+	result := newResult(p.spec.Version)
+	updated := p.updated.Format("2006-01-02")
+
+	c := criterion{
+		name:     "offered_on_platform",
+		expected: fmt.Sprintf("On the %s list for %s accounts", p.spec.Name, strings.Join(p.spec.AccountTypes, "/")),
+		actual:   fmt.Sprintf("Ticker: %s, ISIN: %s", etf.Ticker, etf.ISIN),
+		source: domain.DataSource{
+			Type:        "PlatformInstrumentList",
+			Provider:    p.spec.Name,
+			URL:         p.spec.Source,
+			AccessDate:  p.updated,
+			Reliability: "Primary",
+		},
+	}
+
+	if !p.offers(etf) {
+		c.fail(&result, fmt.Sprintf("Not offered on %s for this account type (list updated %s)", p.spec.Name, updated))
+		result.Status = domain.StatusNotOffered
+		return result
+	}
+
+	c.pass(&result, fmt.Sprintf("Offered on %s (list updated %s)", p.spec.Name, updated))
+	if time.Since(p.updated) > platformListMaxAge {
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("⚠ %s list is more than 90 days old - confirm the ETF is still offered", p.spec.Name))
+		capConfidence(&result, domain.ConfidenceMedium)
+	}
+	return result
+}
+
+// offers matches the ETF's ticker, ticker without exchange suffix, or ISIN
+func (p *PlatformOverlay) offers(etf domain.ETF) bool {
+	ticker := normalize(etf.Ticker)
+	if base, _, found := strings.Cut(ticker, "."); found && p.instruments[base] {
+		return true
+	}
+	return p.instruments[ticker] || (etf.ISIN != "" && p.instruments[normalize(etf.ISIN)])
+}
@@ -0,0 +1,147 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"upstonk/internal/domain"
+	"upstonk/internal/service/reg28"
+)
+
+// Reg28SouthAfricaRules implements eligibility rules for South African retirement
+// annuities and preservation funds
+// Based on: Pension Funds Act 24 of 1956, Regulation 28 (as amended 2022)
+// Reg 28 limits apply to the whole portfolio, so a fund that exceeds a limit on
+// its own is conditional: it can be held alongside other assets up to a maximum weight.
+type Reg28SouthAfricaRules struct {
+	version string
+	limits  reg28.Limits
+}
+
+func NewReg28SouthAfricaRules() *Reg28SouthAfricaRules {
+	return &Reg28SouthAfricaRules{
+		version: reg28.Version,
+		limits:  reg28.DefaultLimits(),
+	}
+}
+
+func (r *Reg28SouthAfricaRules) Name() string {
+	return "REG28_ZA"
+}
+
+func (r *Reg28SouthAfricaRules) Version() string {
+	return r.version
+}
+
+func (r *Reg28SouthAfricaRules) AppliesTo(country, accountType string) bool {
+	if strings.ToUpper(country) != "ZA" {
+		return false
+	}
+	switch strings.ToLower(accountType) {
+	case "retirement_annuity", "preservation_fund":
+		return true
+	}
+	return false
+}
+
+var reg28Descriptions = map[string]string{
+	reg28.CategoryOffshore:     "offshore",
+	reg28.CategoryEquity:       "equity",
+	reg28.CategoryProperty:     "property",
+	reg28.CategoryCommodities:  "commodity",
+	reg28.CategorySingleIssuer: "single-issuer",
+}
+
+// Evaluate checks the ETF's structure and its standalone use of each Reg 28 limit
+func (r *Reg28SouthAfricaRules) Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult {
+	result := newResult(r.version)
+
+	// Rule 1: No borrowing or leveraged/short derivative exposure
+	r.checkStructure(&result, etf)
+
+	// Rule 2: Standalone exposure per limit category
+	exposure := reg28.ExposureOf(etf)
+	for _, category := range r.limits.Categories() {
+		r.checkLimit(&result, etf, exposure, category)
+	}
+
+	// Every limit may legitimately be unverified for a single fund
+	finalize(&result, len(r.limits.Categories()), "your retirement fund administrator")
+
+	return result
+}
+
+func (r *Reg28SouthAfricaRules) checkStructure(result *domain.EligibilityResult, etf domain.ETF) {
+	leverage := criterion{
+		name:     "no_leverage",
+		expected: "No leverage (Reg 28 prohibits borrowing)",
+		actual:   fmt.Sprintf("Leveraged: %t", etf.IsLeveraged),
+		source:   fieldSource(etf, "isLeveraged"),
+	}
+	if etf.IsLeveraged {
+		leverage.fail(result, "Leveraged ETFs are not permitted under Regulation 28")
+	} else {
+		leverage.pass(result, "Not leveraged")
+	}
+
+	inverse := criterion{
+		name:     "no_inverse",
+		expected: "No short exposure (derivatives for hedging only)",
+		actual:   fmt.Sprintf("Inverse: %t", etf.IsInverse),
+		source:   fieldSource(etf, "isInverse"),
+	}
+	if etf.IsInverse {
+		inverse.fail(result, "Inverse ETFs are not permitted under Regulation 28")
+	} else {
+		inverse.pass(result, "Not inverse")
+	}
+}
+
+func (r *Reg28SouthAfricaRules) checkLimit(result *domain.EligibilityResult, etf domain.ETF, exposure reg28.Exposure, category string) {
+	limit := r.limits.Of(category)
+	description := reg28Descriptions[category]
+
+	c := criterion{
+		name:     "reg28_" + category + "_limit",
+		expected: fmt.Sprintf("Portfolio %s exposure ≤ %.0f%%", description, limit),
+		actual:   "Fund exposure: not reported",
+		source:   fieldSource(etf, reg28SourceField(category)),
+	}
+
+	if !exposure.Known[category] {
+		c.unknown(result, fmt.Sprintf("No %s exposure data - portfolio %s limit cannot be checked", description, description),
+			domain.ConfidenceLow)
+		return
+	}
+
+	value := exposure.Values[category]
+	c.actual = fmt.Sprintf("Fund exposure: %.1f%%", value)
+
+	if value <= limit {
+		c.pass(result, fmt.Sprintf("%s exposure %.1f%% within the %.0f%% limit", capitalize(description), value, limit))
+		return
+	}
+
+	maxWeight := limit / value * 100
+	c.unknown(result, fmt.Sprintf("%s exposure %.1f%% exceeds the %.0f%% limit on its own - hold at most %.0f%% of the portfolio in this fund",
+		capitalize(description), value, limit, maxWeight), domain.ConfidenceMedium)
+}
+
+// reg28SourceField maps a limit category to the ETF field it is derived from
+func reg28SourceField(category string) string {
+	switch category {
+	case reg28.CategoryOffshore:
+		return "geographicExposure"
+	case reg28.CategorySingleIssuer:
+		return "topHoldings"
+	}
+	return "assetExposure"
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
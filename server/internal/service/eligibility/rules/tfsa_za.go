@@ -3,8 +3,8 @@ package rules
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
-	"time"
 
 	"upstonk/internal/domain"
 )
@@ -34,20 +34,10 @@ func (r *TFSASouthAfricaRules) AppliesTo(country, accountType string) bool {
 	return strings.ToUpper(country) == "ZA" && strings.ToLower(accountType) == "tfsa"
 }
 
-// Evaluate performs comprehensive TFSA eligibility check
+// Evaluate performs comprehensive TFSA eligibility check. Every criterion
+// records evidence citing the data source it relied on.
 func (r *TFSASouthAfricaRules) Evaluate(ctx context.Context, etf domain.ETF) domain.EligibilityResult {
-	result := domain.EligibilityResult{
-		RuleVersion:  r.version,
-		EvaluatedAt:  time.Now(),
-		Status:       domain.StatusEligible,
-		IsEligible:   true,
-		Confidence:   domain.ConfidenceHigh,
-		RulesPassed:  []string{},
-		RulesFailed:  []string{},
-		RulesSkipped: []string{},
-		Evidence:     []domain.EligibilityEvidence{},
-		Reasons:      []string{},
-	}
+	result := newResult(r.version)
 
 	// Rule 1: Must be JSE-listed
 	r.checkJSEListing(&result, etf)
@@ -68,246 +58,156 @@ func (r *TFSASouthAfricaRules) Evaluate(ctx context.Context, etf domain.ETF) dom
 	r.checkReplication(&result, etf)
 
 	// Determine final status
-	r.finalizeResult(&result, etf)
+	finalize(&result, 3, "SARS or your platform")
 
 	return result
 }
 
 func (r *TFSASouthAfricaRules) checkJSEListing(result *domain.EligibilityResult, etf domain.ETF) {
-	criterion := "jse_listing"
-	expected := "Listed on JSE (Johannesburg Stock Exchange)"
-	actual := fmt.Sprintf("Exchange: %s, Country: %s", etf.Exchange, etf.ExchangeCountry)
+	c := criterion{
+		name:     "jse_listing",
+		expected: "Listed on JSE (Johannesburg Stock Exchange)",
+		actual:   fmt.Sprintf("Exchange: %s, Country: %s", etf.Exchange, etf.ExchangeCountry),
+		source:   typedSource(etf, "ExchangeListing", "exchange"),
+	}
 
 	isJSE := strings.ToUpper(etf.Exchange) == "JSE" ||
 		strings.ToUpper(etf.ExchangeCountry) == "ZA" ||
 		strings.Contains(strings.ToUpper(etf.Exchange), "JOHANNESBURG")
 
-	evidence := domain.EligibilityEvidence{
-		Criterion: criterion,
-		Expected:  expected,
-		Actual:    actual,
-		Result:    "fail",
-	}
-
-	// Find supporting data source
-	for _, ds := range etf.DataSources {
-		if ds.Type == "ExchangeListing" {
-			evidence.DataSource = ds
-			break
-		}
-	}
-
 	if isJSE {
-		evidence.Result = "pass"
-		result.RulesPassed = append(result.RulesPassed, criterion)
-		result.Reasons = append(result.Reasons, "✓ Listed on JSE")
+		c.pass(result, "Listed on JSE")
 	} else {
-		evidence.Result = "fail"
-		result.RulesFailed = append(result.RulesFailed, criterion)
-		result.Reasons = append(result.Reasons, "✗ Not listed on JSE - TFSA requires JSE-listed instruments")
-		result.IsEligible = false
+		c.fail(result, "Not listed on JSE - TFSA requires JSE-listed instruments")
 	}
-
-	result.Evidence = append(result.Evidence, evidence)
 }
 
 func (r *TFSASouthAfricaRules) checkCurrency(result *domain.EligibilityResult, etf domain.ETF) {
-	criterion := "currency_denomination"
-	expected := "ZAR (South African Rand) or USD for approved foreign ETFs"
-	actual := fmt.Sprintf("Currency: %s", etf.Currency)
+	c := criterion{
+		name:     "currency_denomination",
+		expected: "ZAR (South African Rand) or USD for approved foreign ETFs",
+		actual:   fmt.Sprintf("Currency: %s", etf.Currency),
+		source:   fieldSource(etf, "currency"),
+	}
 
 	approvedCurrencies := map[string]bool{
 		"ZAR": true,
+		"ZAC": true, // JSE quotes are in cents
 		"USD": true, // Some JSE-listed ETFs are USD-denominated
 	}
 
-	evidence := domain.EligibilityEvidence{
-		Criterion: criterion,
-		Expected:  expected,
-		Actual:    actual,
-	}
-
 	if approvedCurrencies[strings.ToUpper(etf.Currency)] {
-		evidence.Result = "pass"
-		result.RulesPassed = append(result.RulesPassed, criterion)
-		result.Reasons = append(result.Reasons, fmt.Sprintf("✓ Currency: %s", etf.Currency))
+		c.pass(result, fmt.Sprintf("Currency: %s", etf.Currency))
 	} else if etf.Currency == "" {
-		evidence.Result = "unknown"
-		result.RulesSkipped = append(result.RulesSkipped, criterion)
-		result.Confidence = domain.ConfidenceMedium
-		result.Reasons = append(result.Reasons, "⚠ Currency not specified - manual verification required")
+		c.unknown(result, "Currency not specified - manual verification required", domain.ConfidenceMedium)
 	} else {
-		evidence.Result = "fail"
-		result.RulesFailed = append(result.RulesFailed, criterion)
-		result.Reasons = append(result.Reasons, fmt.Sprintf("✗ Currency %s may not be TFSA-eligible", etf.Currency))
-		result.IsEligible = false
+		c.fail(result, fmt.Sprintf("Currency %s may not be TFSA-eligible", etf.Currency))
 	}
-
-	result.Evidence = append(result.Evidence, evidence)
 }
 
 func (r *TFSASouthAfricaRules) checkETFStructure(result *domain.EligibilityResult, etf domain.ETF) {
 	// Check leveraged
-	leveragedCriterion := "no_leverage"
+	leverage := criterion{
+		name:     "no_leverage",
+		expected: "Non-leveraged ETF",
+		actual:   fmt.Sprintf("Leveraged: %t", etf.IsLeveraged),
+		source:   fieldSource(etf, "isLeveraged"),
+	}
 	if etf.IsLeveraged {
-		result.RulesFailed = append(result.RulesFailed, leveragedCriterion)
-		result.Reasons = append(result.Reasons, "✗ Leveraged ETFs are not permitted in TFSAs")
-		result.IsEligible = false
-		result.Evidence = append(result.Evidence, domain.EligibilityEvidence{
-			Criterion: leveragedCriterion,
-			Expected:  "Non-leveraged ETF",
-			Actual:    "Leveraged ETF detected",
-			Result:    "fail",
-		})
+		leverage.fail(result, "Leveraged ETFs are not permitted in TFSAs")
 	} else {
-		result.RulesPassed = append(result.RulesPassed, leveragedCriterion)
-		result.Reasons = append(result.Reasons, "✓ Not leveraged")
+		leverage.pass(result, "Not leveraged")
 	}
 
 	// Check inverse
-	inverseCriterion := "no_inverse"
+	inverse := criterion{
+		name:     "no_inverse",
+		expected: "Standard tracking ETF",
+		actual:   fmt.Sprintf("Inverse: %t", etf.IsInverse),
+		source:   fieldSource(etf, "isInverse"),
+	}
 	if etf.IsInverse {
-		result.RulesFailed = append(result.RulesFailed, inverseCriterion)
-		result.Reasons = append(result.Reasons, "✗ Inverse ETFs are not permitted in TFSAs")
-		result.IsEligible = false
-		result.Evidence = append(result.Evidence, domain.EligibilityEvidence{
-			Criterion: inverseCriterion,
-			Expected:  "Standard tracking ETF",
-			Actual:    "Inverse ETF detected",
-			Result:    "fail",
-		})
+		inverse.fail(result, "Inverse ETFs are not permitted in TFSAs")
 	} else {
-		result.RulesPassed = append(result.RulesPassed, inverseCriterion)
-		result.Reasons = append(result.Reasons, "✓ Not inverse")
+		inverse.pass(result, "Not inverse")
 	}
 }
 
+// Known TFSA-approved providers in South Africa
+var tfsaApprovedProviders = []string{
+	"satrix",
+	"coreshares",
+	"1nvest",
+	"cloud atlas",
+	"absa",
+	"standardbank",
+	"sygnia",
+	"ashburton",
+}
+
 func (r *TFSASouthAfricaRules) checkProvider(result *domain.EligibilityResult, etf domain.ETF) {
-	criterion := "approved_provider"
-
-	// Known TFSA-approved providers in South Africa
-	approvedProviders := map[string]bool{
-		"satrix":       true,
-		"coreshares":   true,
-		"1nvest":       true,
-		"cloud atlas":  true,
-		"absa":         true,
-		"standardbank": true,
-		"sygnia":       true,
-		"ashburton":    true,
+	c := criterion{
+		name:     "approved_provider",
+		expected: "Recognized SA ETF provider",
+		actual:   fmt.Sprintf("Provider: %s", etf.Provider),
+		source:   fieldSource(etf, "provider"),
 	}
 
-	providerLower := strings.ToLower(etf.Provider)
-
 	// Check if provider is in approved list
+	providerLower := strings.ToLower(etf.Provider)
 	isApproved := false
-	for approved := range approvedProviders {
+	for _, approved := range tfsaApprovedProviders {
 		if strings.Contains(providerLower, approved) {
 			isApproved = true
 			break
 		}
 	}
 
-	evidence := domain.EligibilityEvidence{
-		Criterion: criterion,
-		Expected:  "Recognized SA ETF provider",
-		Actual:    fmt.Sprintf("Provider: %s", etf.Provider),
-	}
-
 	if isApproved {
-		evidence.Result = "pass"
-		result.RulesPassed = append(result.RulesPassed, criterion)
-		result.Reasons = append(result.Reasons, fmt.Sprintf("✓ Approved provider: %s", etf.Provider))
+		c.pass(result, fmt.Sprintf("Approved provider: %s", etf.Provider))
 	} else if etf.Provider == "" {
-		evidence.Result = "unknown"
-		result.RulesSkipped = append(result.RulesSkipped, criterion)
-		result.Confidence = domain.ConfidenceLow
-		result.Reasons = append(result.Reasons, "⚠ Provider not identified - verification required")
+		c.unknown(result, "Provider not identified - verification required", domain.ConfidenceLow)
 	} else {
-		evidence.Result = "unknown"
-		result.RulesSkipped = append(result.RulesSkipped, criterion)
-		result.Confidence = domain.ConfidenceMedium
-		result.Reasons = append(result.Reasons, fmt.Sprintf("⚠ Provider '%s' not in known approved list - verify with platform", etf.Provider))
+		c.unknown(result, fmt.Sprintf("Provider '%s' not in known approved list - verify with platform", etf.Provider),
+			domain.ConfidenceMedium)
 	}
-
-	result.Evidence = append(result.Evidence, evidence)
 }
 
 func (r *TFSASouthAfricaRules) checkImplicitApproval(result *domain.EligibilityResult, etf domain.ETF) {
 	// In South Africa, JSE listing + local provider generally implies TFSA eligibility
 	// However, we should be cautious
-
-	hasJSE := false
-	for _, rule := range result.RulesPassed {
-		if rule == "jse_listing" {
-			hasJSE = true
-			break
-		}
-	}
-
-	hasProvider := false
-	for _, rule := range result.RulesPassed {
-		if rule == "approved_provider" {
-			hasProvider = true
-			break
-		}
+	c := criterion{
+		name:     "implicit_sars_approval",
+		expected: "JSE-listed by an approved provider",
+		actual:   fmt.Sprintf("Exchange: %s, Provider: %s", etf.Exchange, etf.Provider),
+		source:   typedSource(etf, "ExchangeListing", "exchange"),
 	}
 
-	criterion := "implicit_sars_approval"
-	if hasJSE && hasProvider {
-		result.RulesPassed = append(result.RulesPassed, criterion)
-		result.Reasons = append(result.Reasons, "✓ JSE-listed by approved provider (typical TFSA eligibility path)")
+	if slices.Contains(result.RulesPassed, "jse_listing") && slices.Contains(result.RulesPassed, "approved_provider") {
+		c.pass(result, "JSE-listed by approved provider (typical TFSA eligibility path)")
 	} else {
-		result.RulesSkipped = append(result.RulesSkipped, criterion)
-		result.Reasons = append(result.Reasons, "⚠ Cannot confirm implicit TFSA approval - recommend platform verification")
-		if result.Confidence == domain.ConfidenceHigh {
-			result.Confidence = domain.ConfidenceMedium
-		}
+		c.unknown(result, "Cannot confirm implicit TFSA approval - recommend platform verification", domain.ConfidenceMedium)
 	}
 }
 
 func (r *TFSASouthAfricaRules) checkReplication(result *domain.EligibilityResult, etf domain.ETF) {
-	criterion := "replication_method"
-
-	if etf.IsSynthetic {
-		result.RulesSkipped = append(result.RulesSkipped, criterion)
-		result.Reasons = append(result.Reasons, "⚠ Synthetic replication - verify TFSA approval with provider")
-		result.Confidence = domain.ConfidenceMedium
-
-		result.Evidence = append(result.Evidence, domain.EligibilityEvidence{
-			Criterion: criterion,
-			Expected:  "Physical replication preferred",
-			Actual:    "Synthetic replication",
-			Result:    "unknown",
-		})
-	} else if etf.IsPhysical {
-		result.RulesPassed = append(result.RulesPassed, criterion)
-		result.Reasons = append(result.Reasons, "✓ Physical replication")
-	}
-}
-
-func (r *TFSASouthAfricaRules) finalizeResult(result *domain.EligibilityResult, etf domain.ETF) {
-	// Determine final status based on rules
-	if len(result.RulesFailed) > 0 {
-		result.Status = domain.StatusIneligible
-		result.IsEligible = false
-	} else if len(result.RulesSkipped) > 3 || result.Confidence == domain.ConfidenceLow {
-		result.Status = domain.StatusUnknown
-		result.IsEligible = false
-		result.Reasons = append(result.Reasons,
-			"⚠ Insufficient data to confirm eligibility - recommend verification with SARS or your platform")
-	} else if len(result.RulesSkipped) > 0 {
-		result.Status = domain.StatusConditional
-		result.IsEligible = true // Conditional means eligible but with warnings
-		result.Reasons = append(result.Reasons,
-			"✓ Likely eligible but verification recommended before investing")
-	} else {
-		result.Status = domain.StatusEligible
-		result.IsEligible = true
-	}
-
-	// Lower confidence if we have missing data
-	if result.Confidence == domain.ConfidenceHigh && len(result.RulesSkipped) > 0 {
-		result.Confidence = domain.ConfidenceMedium
+	c := criterion{
+		name:     "replication_method",
+		expected: "Physical replication preferred",
+		actual: fmt.Sprintf("Replication: %s, Physical: %t, Synthetic: %t",
+			etf.ReplicationMethod, etf.IsPhysical, etf.IsSynthetic),
+		source: fieldSource(etf, "isSynthetic"),
+	}
+
+	switch {
+	case etf.IsSynthetic:
+		c.unknown(result, "Synthetic replication - verify TFSA approval with provider", domain.ConfidenceMedium)
+	case etf.IsPhysical:
+		c.pass(result, "Physical replication")
+	default:
+		// Not reported: assumed physical as before; the uncited evidence
+		// lowers evidenceCompleteness without changing the outcome
+		c.source = domain.DataSource{}
+		c.pass(result, "Replication method not reported - assumed physical")
 	}
 }
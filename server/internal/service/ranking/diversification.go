@@ -0,0 +1,135 @@
+package ranking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// Share of the diversification score each kind of data carries
+const (
+	holdingsShare  = 0.5
+	sectorsShare   = 0.25
+	countriesShare = 0.25
+)
+
+// unreportedScore is given to an input the ETF does not report: as low as a
+// single country, so a fund cannot gain by withholding data
+var unreportedScore = countryScore(1)
+
+// diversification scores how widely an ETF spreads its assets, from holding
+// concentration, sector spread and country count. detail summarises the
+// inputs for the explanation.
+func diversification(etf domain.ETF) (score float64, detail string) {
+	total := 0.0
+	var parts, missing []string
+
+	if top10, hhi, ok := holdingConcentration(etf.TopHoldings); ok {
+		// A top 10 of 20% or less scores 1, 100% scores 0. An HHI of 0.25 or
+		// more (four equal holdings) scores 0; only the listed holdings count,
+		// so it is a lower bound.
+		top10Score := clamp((100 - top10) / 80)
+		hhiScore := clamp(1 - hhi/0.25)
+		total += holdingsShare * (top10Score + hhiScore) / 2
+		parts = append(parts, fmt.Sprintf("top 10 holdings %.0f%%, HHI %.3f", top10, hhi))
+	} else {
+		total += holdingsShare * unreportedScore
+		missing = append(missing, "holdings")
+	}
+
+	if effective, count, ok := sectorSpread(etf.SectorExposure); ok {
+		// One sector scores 0; eight or more equally weighted sectors score 1
+		total += sectorsShare * clamp((effective-1)/7)
+		parts = append(parts, fmt.Sprintf("%d sectors (%.1f effective)", count, effective))
+	} else {
+		total += sectorsShare * unreportedScore
+		missing = append(missing, "sectors")
+	}
+
+	if countries := countryCount(etf.GeographicExposure); countries > 0 {
+		total += countriesShare * countryScore(countries)
+		parts = append(parts, fmt.Sprintf("%d countries", countries))
+	} else {
+		total += countriesShare * unreportedScore
+		missing = append(missing, "countries")
+	}
+
+	if len(missing) > 0 {
+		parts = append(parts, fmt.Sprintf("%s not reported (scored %.1f)", strings.Join(missing, ", "), unreportedScore))
+	}
+	return total, strings.Join(parts, ", ")
+}
+
+// holdingConcentration returns the combined weight of the ten largest
+// holdings (%) and the Herfindahl index of the listed holdings (0-1)
+func holdingConcentration(holdings []domain.Holding) (top10, hhi float64, ok bool) {
+	weights := make([]float64, 0, len(holdings))
+	for _, holding := range holdings {
+		if holding.Weight > 0 {
+			weights = append(weights, min(holding.Weight, 100))
+		}
+	}
+	if len(weights) == 0 {
+		return 0, 0, false
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(weights)))
+	for i, weight := range weights {
+		if i < 10 {
+			top10 += weight
+		}
+		hhi += (weight / 100) * (weight / 100)
+	}
+	return min(top10, 100), hhi, true
+}
+
+// sectorSpread returns the effective number of sectors (the inverse
+// Herfindahl index of sector weights) and the number reported
+func sectorSpread(sectors []domain.SectorAllocation) (effective float64, count int, ok bool) {
+	weights := make(map[string]float64)
+	total := 0.0
+	for _, sector := range sectors {
+		if sector.Percentage > 0 {
+			weights[strings.ToLower(strings.TrimSpace(sector.Sector))] += sector.Percentage
+			total += sector.Percentage
+		}
+	}
+	if total == 0 {
+		return 0, 0, false
+	}
+
+	hhi := 0.0
+	for _, weight := range weights {
+		hhi += (weight / total) * (weight / total)
+	}
+	return 1 / hhi, len(weights), true
+}
+
+// countryCount is the number of countries the ETF reports holding
+func countryCount(exposure domain.GeographicExposure) int {
+	count := 0
+	for _, weight := range exposure.Countries {
+		if weight > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func countryScore(countries int) float64 {
+	switch {
+	case countries >= 10:
+		return 1.0
+	case countries >= 5:
+		return 0.8
+	case countries >= 2:
+		return 0.5
+	}
+	return 0.2
+}
+
+func clamp(value float64) float64 {
+	return max(0, min(1, value))
+}
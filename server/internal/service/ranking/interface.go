@@ -1,11 +1,11 @@
 package ranking
 
 import (
-	"upstonk/internal/api/dto"
 	"upstonk/internal/domain"
 )
 
-// Engine scores and ranks ETFs
+// Engine scores and ranks ETFs. Scoring sees the whole discovered ETF so
+// investor-specific estimates such as tax drag can count.
 type Engine interface {
-	Score(etf domain.ETF, preferences dto.RankingPreferences) domain.RankingScore
+	Score(discovered domain.DiscoveredETF, weights Weights) domain.RankingScore
 }
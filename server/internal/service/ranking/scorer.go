@@ -1,7 +1,10 @@
 package ranking
 
 import (
-	"upstonk/internal/api/dto"
+	"fmt"
+	"sort"
+	"strings"
+
 	"upstonk/internal/domain"
 )
 
@@ -11,53 +14,75 @@ func NewWeightedScorer() Engine {
 	return &WeightedScorer{}
 }
 
-func (s *WeightedScorer) Score(etf domain.ETF, preferences dto.RankingPreferences) domain.RankingScore {
+func (s *WeightedScorer) Score(discovered domain.DiscoveredETF, weights Weights) domain.RankingScore {
+	etf := discovered.ETF
 	scores := make(map[string]float64)
 
-	// Fee score (inverse - lower is better)
-	scores["fees"] = s.scoreFees(etf.TER)
+	// Cost score (inverse - lower is better), all-in cost when estimated
+	annualCost := etf.TER
+	if discovered.Costs != nil {
+		annualCost = discovered.Costs.EffectiveCost
+	}
+	scores[LowestFees] = s.scoreFees(annualCost)
 
 	// Liquidity score
-	scores["liquidity"] = s.scoreLiquidity(etf.AverageDailyVolume)
+	scores[Liquidity] = s.scoreLiquidity(etf.AverageDailyVolume)
 
 	// Size/stability score
-	scores["stability"] = s.scoreAUM(etf.AUM)
+	scores[FundSize] = s.scoreAUM(etf.AUM)
 
 	// Tracking score
-	scores["tracking"] = s.scoreTracking(etf.TrackingDifference)
+	scores[TrackingAccuracy] = s.scoreTracking(etf.TrackingDifference)
 
-	// Apply weights
-	weights := preferences.Weighting
-	if len(weights) == 0 {
-		// Default weights
-		weights = map[string]float64{
-			"fees":      0.4,
-			"liquidity": 0.3,
-			"tracking":  0.2,
-			"stability": 0.1,
-		}
-	}
+	// Diversification score (holding concentration, sector and country spread)
+	var spread string
+	scores[Diversification], spread = diversification(etf)
+
+	// Tax efficiency score (dividend withholding drag)
+	scores[TaxEfficiency] = s.scoreTax(discovered.TaxDrag)
 
+	// Apply weights
 	totalScore := 0.0
 	for component, score := range scores {
-		if weight, exists := weights[component]; exists {
-			totalScore += score * weight
-		}
+		totalScore += score * weights.Values[component]
 	}
 
 	return domain.RankingScore{
 		TotalScore:      totalScore * 100, // Scale to 0-100
 		ComponentScores: scores,
-		Explanation:     "Weighted score based on fees, liquidity, tracking, and stability",
+		Explanation:     explain(weights) + "; diversification: " + spread,
 	}
 }
 
-func (s *WeightedScorer) scoreFees(ter float64) float64 {
-	// Lower TER = higher score
-	if ter >= 1.0 {
+// explain lists the weighted components, heaviest first
+func explain(weights Weights) string {
+	components := make([]string, 0, len(weights.Values))
+	for component, weight := range weights.Values {
+		if weight > 0 {
+			components = append(components, component)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool {
+		wi, wj := weights.Values[components[i]], weights.Values[components[j]]
+		if wi != wj {
+			return wi > wj
+		}
+		return components[i] < components[j]
+	})
+
+	parts := make([]string, 0, len(components))
+	for _, component := range components {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", component, weights.Values[component]*100))
+	}
+	return fmt.Sprintf("Weighted score (%s weights): %s", weights.Method, strings.Join(parts, ", "))
+}
+
+func (s *WeightedScorer) scoreFees(annualCost float64) float64 {
+	// Lower cost (% per year) = higher score
+	if annualCost >= 1.0 {
 		return 0.0
 	}
-	return 1.0 - ter
+	return 1.0 - annualCost
 }
 
 func (s *WeightedScorer) scoreLiquidity(avgVolume float64) float64 {
@@ -98,3 +123,14 @@ func (s *WeightedScorer) scoreTracking(trackingDiff float64) float64 {
 	}
 	return 0.4
 }
+
+func (s *WeightedScorer) scoreTax(drag *domain.TaxDrag) float64 {
+	// Lower annual drag = better; a 1% drag scores zero
+	if drag == nil {
+		return 0.7 // Unknown, neutral score
+	}
+	if drag.AnnualDrag >= 1.0 {
+		return 0.0
+	}
+	return 1.0 - drag.AnnualDrag
+}
@@ -0,0 +1,155 @@
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"upstonk/internal/api/dto"
+)
+
+// Score components. The same names are used for rankingPreferences.priority
+// and the keys of rankingPreferences.weighting.
+const (
+	LowestFees       = "lowest_fees"
+	TrackingAccuracy = "tracking_accuracy"
+	Liquidity        = "liquidity"
+	FundSize         = "fund_size"
+	Diversification  = "diversification"
+	TaxEfficiency    = "tax_efficiency"
+)
+
+// Components lists every score component
+var Components = []string{LowestFees, TrackingAccuracy, Liquidity, FundSize, Diversification, TaxEfficiency}
+
+// aliases are the weighting keys accepted before components were named after
+// priorities
+var aliases = map[string]string{
+	"fees":      LowestFees,
+	"tracking":  TrackingAccuracy,
+	"stability": FundSize,
+	"tax":       TaxEfficiency,
+}
+
+// defaultWeights apply when neither a priority nor a weighting is given
+var defaultWeights = map[string]float64{
+	LowestFees:       0.4,
+	Liquidity:        0.3,
+	TrackingAccuracy: 0.2,
+	FundSize:         0.1,
+}
+
+// Weighting methods
+const (
+	MethodDefault   = "default"
+	MethodPriority  = "rank_order_centroid"
+	MethodWeighting = "custom"
+)
+
+// Weights are the share of the ranking score each component gets, summing to 1
+type Weights struct {
+	Method string
+	Values map[string]float64
+}
+
+// Component resolves a priority or weighting key, including legacy aliases,
+// to a component name
+func Component(key string) (string, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if canonical, ok := aliases[key]; ok {
+		return canonical, true
+	}
+	for _, component := range Components {
+		if key == component {
+			return component, true
+		}
+	}
+	return "", false
+}
+
+// ResolveWeights turns ranking preferences into component weights. An explicit
+// weighting wins; otherwise the priority list is converted with rank-order
+// centroid weights; otherwise the defaults apply.
+func ResolveWeights(preferences dto.RankingPreferences) (Weights, error) {
+	if len(preferences.Weighting) > 0 {
+		return customWeights(preferences.Weighting)
+	}
+	if len(preferences.Priority) > 0 {
+		return priorityWeights(preferences.Priority)
+	}
+
+	values := make(map[string]float64, len(defaultWeights))
+	for component, weight := range defaultWeights {
+		values[component] = weight
+	}
+	return Weights{Method: MethodDefault, Values: values}, nil
+}
+
+func customWeights(weighting map[string]float64) (Weights, error) {
+	keys := make([]string, 0, len(weighting))
+	for key := range weighting {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make(map[string]float64, len(weighting))
+	sum := 0.0
+	for _, key := range keys {
+		component, ok := Component(key)
+		if !ok {
+			return Weights{}, fmt.Errorf("unknown ranking weight '%s', expected one of %s", key, strings.Join(Components, ", "))
+		}
+		if _, duplicate := values[component]; duplicate {
+			return Weights{}, fmt.Errorf("ranking weight '%s' is given more than once", component)
+		}
+		weight := weighting[key]
+		if weight < 0 || weight > 1 {
+			return Weights{}, fmt.Errorf("ranking weight '%s' must be between 0 and 1, got %.2f", key, weight)
+		}
+		values[component] = weight
+		sum += weight
+	}
+
+	if sum < 0.99 || sum > 1.01 {
+		return Weights{}, fmt.Errorf("ranking weights must sum to 1.0, got %.2f", sum)
+	}
+	for component := range values {
+		values[component] = round4(values[component] / sum)
+	}
+	return Weights{Method: MethodWeighting, Values: values}, nil
+}
+
+// priorityWeights gives the i-th of n priorities (1/n) * sum(1/k, k=i..n),
+// so the first counts most and the weights sum to 1. Components not listed
+// get no weight.
+func priorityWeights(priority []string) (Weights, error) {
+	ordered := make([]string, 0, len(priority))
+	seen := make(map[string]bool, len(priority))
+	for _, key := range priority {
+		component, ok := Component(key)
+		if !ok {
+			return Weights{}, fmt.Errorf("unknown ranking priority '%s', expected one of %s", key, strings.Join(Components, ", "))
+		}
+		if seen[component] {
+			return Weights{}, fmt.Errorf("ranking priority '%s' is listed more than once", component)
+		}
+		seen[component] = true
+		ordered = append(ordered, component)
+	}
+
+	n := float64(len(ordered))
+	values := make(map[string]float64, len(ordered))
+	for i, component := range ordered {
+		weight := 0.0
+		for k := i + 1; k <= len(ordered); k++ {
+			weight += 1 / float64(k)
+		}
+		values[component] = round4(weight / n)
+	}
+	return Weights{Method: MethodPriority, Values: values}, nil
+}
+
+func round4(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
@@ -0,0 +1,321 @@
+// Package reg28 measures ETF and portfolio exposure against the asset limits of
+// Regulation 28 of the Pension Funds Act, which bind South African retirement
+// annuities, preservation funds and pension/provident funds.
+package reg28
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// Version identifies the limit set (Regulation 28 as amended, effective 3 January 2023)
+const Version = "reg28_za_v1.0_2023"
+
+// Limit categories
+const (
+	CategoryOffshore     = "offshore"
+	CategoryEquity       = "equity"
+	CategoryProperty     = "property"
+	CategoryCommodities  = "commodities"
+	CategorySingleIssuer = "single_issuer"
+)
+
+// Utilisation statuses
+const (
+	StatusWithin  = "within"
+	StatusBreach  = "breach"
+	StatusUnknown = "unknown"
+)
+
+// Limits are maximum portfolio percentages per category
+type Limits struct {
+	Offshore     float64
+	Equity       float64
+	Property     float64
+	Commodities  float64
+	SingleIssuer float64 // Any one listed equity issuer (large-cap allowance)
+}
+
+// DefaultLimits returns the Regulation 28 limits in force since 2023
+func DefaultLimits() Limits {
+	return Limits{
+		Offshore:     45,
+		Equity:       75,
+		Property:     25,
+		Commodities:  10,
+		SingleIssuer: 15,
+	}
+}
+
+// Categories lists the limits in reporting order
+func (l Limits) Categories() []string {
+	return []string{CategoryOffshore, CategoryEquity, CategoryProperty, CategoryCommodities, CategorySingleIssuer}
+}
+
+// Of returns the limit for a category
+func (l Limits) Of(category string) float64 {
+	switch category {
+	case CategoryOffshore:
+		return l.Offshore
+	case CategoryEquity:
+		return l.Equity
+	case CategoryProperty:
+		return l.Property
+	case CategoryCommodities:
+		return l.Commodities
+	case CategorySingleIssuer:
+		return l.SingleIssuer
+	}
+	return 0
+}
+
+// Exposure is one ETF's look-through exposure per category, as a percentage of
+// the fund. Categories missing from Known could not be derived from the data.
+type Exposure struct {
+	Values  map[string]float64
+	Known   map[string]bool
+	Issuers map[string]float64 // Largest holdings by issuer name -> percentage
+}
+
+// ExposureOf derives an ETF's Regulation 28 exposure from its asset and
+// geographic breakdown and top holdings
+func ExposureOf(etf domain.ETF) Exposure {
+	exposure := Exposure{
+		Values:  make(map[string]float64),
+		Known:   make(map[string]bool),
+		Issuers: make(map[string]float64),
+	}
+
+	assets := etf.AssetExposure
+	if assets.Equities+assets.Bonds+assets.Cash+assets.Commodities+assets.RealEstate+assets.Other > 0 {
+		exposure.set(CategoryEquity, assets.Equities)
+		exposure.set(CategoryProperty, assets.RealEstate)
+		exposure.set(CategoryCommodities, assets.Commodities)
+	}
+
+	if offshore, ok := offshoreExposure(etf.GeographicExposure); ok {
+		exposure.set(CategoryOffshore, offshore)
+	}
+
+	if len(etf.TopHoldings) > 0 {
+		largest := 0.0
+		for _, holding := range etf.TopHoldings {
+			if !isEquityHolding(holding) {
+				continue
+			}
+			issuer := issuerKey(holding)
+			exposure.Issuers[issuer] += holding.Weight
+			largest = max(largest, exposure.Issuers[issuer])
+		}
+		exposure.set(CategorySingleIssuer, largest)
+	}
+
+	return exposure
+}
+
+func (e Exposure) set(category string, value float64) {
+	e.Values[category] = value
+	e.Known[category] = true
+}
+
+// Region keys that denote South African (domestic) exposure
+var domesticRegions = []string{"za", "south_africa", "south africa", "domestic"}
+
+// offshoreExposure treats everything not explicitly South African as offshore,
+// so partial country breakdowns err on the side of overstating foreign exposure
+func offshoreExposure(geo domain.GeographicExposure) (float64, bool) {
+	if len(geo.Countries) > 0 {
+		return clamp(100 - geo.Countries["ZA"]), true
+	}
+	if len(geo.Regions) > 0 {
+		domestic := 0.0
+		for region, weight := range geo.Regions {
+			for _, alias := range domesticRegions {
+				if strings.EqualFold(strings.TrimSpace(region), alias) {
+					domestic = max(domestic, weight)
+				}
+			}
+		}
+		return clamp(100 - domestic), true
+	}
+	return 0, false
+}
+
+func issuerKey(holding domain.Holding) string {
+	if holding.Name != "" {
+		return holding.Name
+	}
+	return strings.ToUpper(holding.Ticker)
+}
+
+// isEquityHolding excludes bonds and cash, which the single-issuer equity limit does not cover
+func isEquityHolding(holding domain.Holding) bool {
+	assetType := strings.ToLower(holding.AssetType)
+	return assetType == "" || strings.Contains(assetType, "equit") || strings.Contains(assetType, "stock") || strings.Contains(assetType, "share")
+}
+
+func clamp(value float64) float64 {
+	return min(max(value, 0), 100)
+}
+
+// Holding is an ETF and its share of the portfolio (percentage)
+type Holding struct {
+	ETF      domain.ETF
+	Weight   float64
+	Resolved bool // False when no data was found for the ticker
+}
+
+// Report is the aggregate Regulation 28 test for a portfolio
+type Report struct {
+	Status      string             `json:"status"` // "compliant", "breach", "unknown"
+	Compliant   bool               `json:"compliant"`
+	RuleVersion string             `json:"ruleVersion"`
+	TotalWeight float64            `json:"totalWeight"`
+	Limits      []LimitUtilisation `json:"limits"`
+	Warnings    []string           `json:"warnings,omitempty"`
+}
+
+// LimitUtilisation reports how much of one limit the portfolio uses
+type LimitUtilisation struct {
+	Category      string         `json:"category"`
+	Limit         float64        `json:"limit"`         // Maximum % of portfolio
+	Exposure      float64        `json:"exposure"`      // % of portfolio, from holdings with data
+	Utilisation   float64        `json:"utilisation"`   // Exposure as % of the limit
+	Headroom      float64        `json:"headroom"`      // Percentage points left before the limit
+	UnknownWeight float64        `json:"unknownWeight"` // % of portfolio with no data for this category
+	Status        string         `json:"status"`        // "within", "breach", "unknown"
+	Detail        string         `json:"detail,omitempty"`
+	Contributors  []Contribution `json:"contributors,omitempty"`
+}
+
+// Contribution is one holding's share of a category's exposure
+type Contribution struct {
+	Ticker   string  `json:"ticker"`
+	Exposure float64 `json:"exposure"` // % of portfolio
+}
+
+// CheckPortfolio aggregates holding exposures and tests them against limits.
+// A category is unknown when holdings without data could push it over the limit.
+func CheckPortfolio(holdings []Holding, limits Limits) Report {
+	report := Report{
+		RuleVersion: Version,
+		Limits:      make([]LimitUtilisation, 0, len(limits.Categories())),
+		Warnings:    []string{},
+	}
+
+	exposures := make([]Exposure, len(holdings))
+	for i, holding := range holdings {
+		report.TotalWeight += holding.Weight
+		exposures[i] = ExposureOf(holding.ETF)
+		if !holding.Resolved {
+			report.Warnings = append(report.Warnings,
+				fmt.Sprintf("No data found for %s - its %.1f%% weight is treated as unknown", holding.ETF.Ticker, holding.Weight))
+		}
+	}
+
+	for _, category := range limits.Categories() {
+		var usage LimitUtilisation
+		if category == CategorySingleIssuer {
+			usage = issuerUtilisation(holdings, exposures)
+		} else {
+			usage = categoryUtilisation(category, holdings, exposures)
+		}
+
+		usage.Category = category
+		usage.Limit = limits.Of(category)
+		usage.Utilisation = round(usage.Exposure / usage.Limit * 100)
+		usage.Headroom = round(usage.Limit - usage.Exposure)
+		usage.Exposure = round(usage.Exposure)
+		usage.UnknownWeight = round(usage.UnknownWeight)
+
+		switch {
+		case usage.Exposure > usage.Limit:
+			usage.Status = StatusBreach
+		case usage.Exposure+usage.UnknownWeight > usage.Limit:
+			usage.Status = StatusUnknown
+		default:
+			usage.Status = StatusWithin
+		}
+
+		report.Limits = append(report.Limits, usage)
+	}
+
+	report.Status = "compliant"
+	for _, usage := range report.Limits {
+		if usage.Status == StatusBreach {
+			report.Status = "breach"
+			break
+		}
+		if usage.Status == StatusUnknown {
+			report.Status = "unknown"
+		}
+	}
+	report.Compliant = report.Status == "compliant"
+	report.TotalWeight = round(report.TotalWeight)
+
+	return report
+}
+
+func categoryUtilisation(category string, holdings []Holding, exposures []Exposure) LimitUtilisation {
+	var usage LimitUtilisation
+	for i, holding := range holdings {
+		if !exposures[i].Known[category] {
+			usage.UnknownWeight += holding.Weight
+			continue
+		}
+		contribution := holding.Weight * exposures[i].Values[category] / 100
+		usage.Exposure += contribution
+		if contribution > 0 {
+			usage.Contributors = append(usage.Contributors, Contribution{Ticker: holding.ETF.Ticker, Exposure: round(contribution)})
+		}
+	}
+	sortContributions(usage.Contributors)
+	return usage
+}
+
+// issuerUtilisation sums each issuer across all holdings and reports the largest.
+// Holdings that disclose no top holdings count as unknown weight.
+func issuerUtilisation(holdings []Holding, exposures []Exposure) LimitUtilisation {
+	var usage LimitUtilisation
+	issuers := make(map[string]float64)
+	contributors := make(map[string][]Contribution)
+
+	for i, holding := range holdings {
+		if !exposures[i].Known[CategorySingleIssuer] {
+			usage.UnknownWeight += holding.Weight
+			continue
+		}
+		for issuer, weight := range exposures[i].Issuers {
+			contribution := holding.Weight * weight / 100
+			issuers[issuer] += contribution
+			contributors[issuer] = append(contributors[issuer], Contribution{Ticker: holding.ETF.Ticker, Exposure: round(contribution)})
+		}
+	}
+
+	largest := ""
+	for issuer, exposure := range issuers {
+		if exposure > usage.Exposure || (exposure == usage.Exposure && issuer < largest) {
+			largest, usage.Exposure = issuer, exposure
+		}
+	}
+	if largest != "" {
+		usage.Detail = "Largest issuer: " + largest
+		usage.Contributors = contributors[largest]
+		sortContributions(usage.Contributors)
+	}
+	return usage
+}
+
+func sortContributions(contributions []Contribution) {
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributions[i].Exposure > contributions[j].Exposure
+	})
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -2,6 +2,8 @@ package search
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -10,214 +12,232 @@ import (
 
 // AggregatedProvider combines multiple data sources for comprehensive ETF discovery
 type AggregatedProvider struct {
-	providers []Provider
-	cache     *ETFCache
+	providers    []Provider
+	cache        *ETFCache
+	inflight     inflightGroup
+	mergePolicy  MergePolicy
+	fetchTimeout time.Duration
 }
 
-func NewAggregatedProvider(providers ...Provider) Provider {
+// NewAggregatedProvider combines providers behind a shared result cache.
+// A nil cache disables caching.
+func NewAggregatedProvider(cache *ETFCache, providers ...Provider) *AggregatedProvider {
 	return &AggregatedProvider{
-		providers: providers,
-		cache:     NewETFCache(),
+		providers:    providers,
+		cache:        cache,
+		mergePolicy:  DefaultMergePolicy(),
+		fetchTimeout: DefaultBreakerConfig().Timeout,
 	}
 }
 
+// SetFetchTimeout bounds a shared upstream fetch. Providers are queried in
+// parallel, so this is normally the per-provider timeout.
+func (a *AggregatedProvider) SetFetchTimeout(timeout time.Duration) {
+	a.fetchTimeout = timeout
+}
+
+// SetMergePolicy replaces the source precedence and conflict tolerance used when merging
+func (a *AggregatedProvider) SetMergePolicy(policy MergePolicy) {
+	a.mergePolicy = policy
+}
+
+// ProviderHealth reports the health of every provider that tracks it
+func (a *AggregatedProvider) ProviderHealth() []ProviderHealth {
+	health := make([]ProviderHealth, 0, len(a.providers))
+	for _, provider := range a.providers {
+		if monitored, ok := provider.(*MonitoredProvider); ok {
+			health = append(health, monitored.Health())
+		}
+	}
+	return health
+}
+
+// CacheStats reports the result cache counters (zero value when caching is disabled)
+func (a *AggregatedProvider) CacheStats() CacheStats {
+	if a.cache == nil {
+		return CacheStats{}
+	}
+	return a.cache.Stats()
+}
+
 func (a *AggregatedProvider) Search(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
+	key := CriteriaKey(criteria)
+	trace := TraceFrom(ctx)
+
 	// Check cache first
-	cacheKey := a.cache.GenerateKey(criteria)
-	if cached, found := a.cache.Get(cacheKey); found {
-		log.Printf("Cache hit for criteria: %+v", criteria)
-		return cached, nil
+	if a.cache != nil {
+		cached, storedAt, state := a.cache.Lookup(key)
+		switch state {
+		case CacheFresh:
+			log.Printf("Cache hit for criteria: %+v", criteria)
+			trace.MarkCacheHit()
+			trace.RecordSource(SourceStatus{Name: "cache", Status: SourceAnswered, Results: len(cached)})
+			return cached, nil
+		case CacheStale:
+			// Serve immediately and revalidate in the background
+			log.Printf("Serving stale results (stored %s) for criteria: %+v", storedAt.Format(time.RFC3339), criteria)
+			trace.MarkCacheHit()
+			trace.MarkStale(storedAt)
+			trace.RecordSource(SourceStatus{Name: "cache", Status: SourceAnswered, Results: len(cached)})
+			a.inflight.start(key, func() fetchResult {
+				return a.fetch(ctx, criteria, key)
+			})
+			return cached, nil
+		}
+	}
+
+	// Concurrent identical searches share one upstream fetch
+	call := a.inflight.start(key, func() fetchResult {
+		return a.fetch(ctx, criteria, key)
+	})
+
+	select {
+	case <-call.done:
+		for _, source := range call.result.sources {
+			trace.RecordSource(source)
+		}
+		return call.result.etfs, call.result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
+}
+
+// fetchResult is the outcome of one upstream fetch, shared by every waiting caller
+type fetchResult struct {
+	etfs    []domain.ETF
+	sources []SourceStatus
+	err     error
+}
+
+// fetch fans out to every provider, merges the results and caches them.
+// It keeps the starting request's values and deadline but not its
+// cancellation, so one caller giving up does not cancel the fetch for others
+// waiting on it; the fetch timeout bounds it either way.
+func (a *AggregatedProvider) fetch(parent context.Context, criteria Criteria, key string) fetchResult {
+	timeout := a.fetchTimeout
+	if deadline, ok := parent.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), timeout)
+	defer cancel()
 
 	// Search all providers in parallel
 	var wg sync.WaitGroup
-	resultsChan := make(chan []domain.ETF, len(a.providers))
-	errorsChan := make(chan error, len(a.providers))
+	results := make([][]domain.ETF, len(a.providers))
+	sources := make([]SourceStatus, len(a.providers))
 
-	for _, provider := range a.providers {
+	for i, provider := range a.providers {
 		wg.Add(1)
-		go func(p Provider) {
+		go func(i int, p Provider) {
 			defer wg.Done()
 
-			results, err := p.Search(ctx, criteria)
-			if err != nil {
+			start := time.Now()
+			etfs, err := p.Search(ctx, criteria)
+			if err != nil && !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrQuotaExhausted) {
 				log.Printf("Provider error: %v", err)
-				errorsChan <- err
-				return
 			}
 
-			resultsChan <- results
-		}(provider)
+			results[i] = etfs
+			sources[i] = sourceStatus(providerName(p), len(etfs), time.Since(start), err)
+		}(i, provider)
 	}
 
-	// Wait for all providers to complete
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-		close(errorsChan)
-	}()
+	wg.Wait()
 
 	// Aggregate results
 	allETFs := make([]domain.ETF, 0)
-	for results := range resultsChan {
-		allETFs = append(allETFs, results...)
+	answered := 0
+	var errs []error
+	for i, source := range sources {
+		if source.Status == SourceAnswered {
+			answered++
+			allETFs = append(allETFs, results[i]...)
+		} else {
+			errs = append(errs, fmt.Errorf("%s %s: %s", source.Name, source.Status, source.Error))
+		}
+	}
+
+	// Nothing answered: report it rather than caching an empty result
+	if answered == 0 && len(a.providers) > 0 {
+		return fetchResult{
+			sources: sources,
+			err:     fmt.Errorf("%w: %w", ErrNoSourceAvailable, errors.Join(errs...)),
+		}
 	}
 
 	// Deduplicate and merge data from multiple sources
 	merged := a.deduplicateAndMerge(allETFs)
 
 	// Cache results
-	a.cache.Set(cacheKey, merged)
-
-	return merged, nil
-}
-
-// deduplicateAndMerge combines ETF data from multiple sources
-func (a *AggregatedProvider) deduplicateAndMerge(etfs []domain.ETF) []domain.ETF {
-	// Group by ticker/ISIN
-	etfMap := make(map[string][]domain.ETF)
-
-	for _, etf := range etfs {
-		key := etf.Ticker
-		if key == "" {
-			key = etf.ISIN
-		}
-
-		etfMap[key] = append(etfMap[key], etf)
+	if a.cache != nil {
+		a.cache.Set(key, merged)
 	}
 
-	// Merge data for each ETF
-	merged := make([]domain.ETF, 0, len(etfMap))
+	return fetchResult{etfs: merged, sources: sources}
+}
 
-	for _, etfGroup := range etfMap {
-		if len(etfGroup) == 1 {
-			merged = append(merged, etfGroup[0])
-			continue
-		}
+// ErrNoSourceAvailable is returned when every provider failed or was skipped
+var ErrNoSourceAvailable = errors.New("no data source available")
 
-		// Merge multiple sources into single ETF with best data
-		mergedETF := a.mergeETFData(etfGroup)
-		merged = append(merged, mergedETF)
-	}
+// inflightGroup de-duplicates concurrent fetches for the same key
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
 
-	return merged
+type inflightCall struct {
+	done   chan struct{}
+	result fetchResult
 }
 
-// mergeETFData combines data from multiple sources, preferring primary sources
-func (a *AggregatedProvider) mergeETFData(etfs []domain.ETF) domain.ETF {
-	// Start with first ETF as base
-	merged := etfs[0]
+// start returns the in-flight call for key, launching fn if none is running
+func (g *inflightGroup) start(key string, fn func() fetchResult) *inflightCall {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	// Combine data sources
-	allSources := make([]domain.DataSource, 0)
-	for _, etf := range etfs {
-		allSources = append(allSources, etf.DataSources...)
+	if g.calls == nil {
+		g.calls = make(map[string]*inflightCall)
 	}
-	merged.DataSources = allSources
-
-	// Take most complete data for each field
-	for _, etf := range etfs[1:] {
-		if merged.Name == "" && etf.Name != "" {
-			merged.Name = etf.Name
-		}
-		if merged.ISIN == "" && etf.ISIN != "" {
-			merged.ISIN = etf.ISIN
-		}
-		if merged.Provider == "" && etf.Provider != "" {
-			merged.Provider = etf.Provider
-		}
-		if merged.TER == 0 && etf.TER != 0 {
-			merged.TER = etf.TER
-		}
-		if merged.AUM == 0 && etf.AUM != 0 {
-			merged.AUM = etf.AUM
-		}
-		if merged.TrackingIndex == "" && etf.TrackingIndex != "" {
-			merged.TrackingIndex = etf.TrackingIndex
-		}
-
-		// Merge holdings (prefer longer list)
-		if len(etf.TopHoldings) > len(merged.TopHoldings) {
-			merged.TopHoldings = etf.TopHoldings
-		}
-
-		// Merge sector exposure (prefer more detailed)
-		if len(etf.SectorExposure) > len(merged.SectorExposure) {
-			merged.SectorExposure = etf.SectorExposure
-		}
-
-		// Merge geographic exposure
-		for region, weight := range etf.GeographicExposure.Regions {
-			if _, exists := merged.GeographicExposure.Regions[region]; !exists {
-				if merged.GeographicExposure.Regions == nil {
-					merged.GeographicExposure.Regions = make(map[string]float64)
-				}
-				merged.GeographicExposure.Regions[region] = weight
-			}
-		}
+	if call, ok := g.calls[key]; ok {
+		return call
 	}
 
-	return merged
-}
+	call := &inflightCall{done: make(chan struct{})}
+	g.calls[key] = call
 
-// ETFCache provides simple in-memory caching
-type ETFCache struct {
-	mu   sync.RWMutex
-	data map[string]cacheEntry
-	ttl  int64 // seconds
-}
+	go func() {
+		call.result = fn()
 
-type cacheEntry struct {
-	etfs      []domain.ETF
-	timestamp int64
-}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
 
-func NewETFCache() *ETFCache {
-	return &ETFCache{
-		data: make(map[string]cacheEntry),
-		ttl:  3600, // 1 hour
-	}
-}
+		close(call.done)
+	}()
 
-func (c *ETFCache) GenerateKey(criteria Criteria) string {
-	// Simple key generation - in production use proper hashing
-	key := criteria.Country
-	for _, sector := range criteria.Sectors {
-		key += "_" + sector
-	}
-	for _, market := range criteria.Markets {
-		key += "_" + market
-	}
-	return key
+	return call
 }
 
-func (c *ETFCache) Get(key string) ([]domain.ETF, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// deduplicateAndMerge combines ETF data from multiple sources
+func (a *AggregatedProvider) deduplicateAndMerge(etfs []domain.ETF) []domain.ETF {
+	// Group by ticker/ISIN
+	etfMap := make(map[string][]domain.ETF)
 
-	entry, exists := c.data[key]
-	if !exists {
-		return nil, false
-	}
+	for _, etf := range etfs {
+		key := etf.Ticker
+		if key == "" {
+			key = etf.ISIN
+		}
 
-	// Check if expired
-	if entry.timestamp+c.ttl < nowUnix() {
-		return nil, false
+		etfMap[key] = append(etfMap[key], etf)
 	}
 
-	return entry.etfs, true
-}
-
-func (c *ETFCache) Set(key string, etfs []domain.ETF) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	// Merge data for each ETF (single-source groups still get field provenance)
+	merged := make([]domain.ETF, 0, len(etfMap))
 
-	c.data[key] = cacheEntry{
-		etfs:      etfs,
-		timestamp: nowUnix(),
+	for _, etfGroup := range etfMap {
+		merged = append(merged, a.mergePolicy.Merge(etfGroup...))
 	}
-}
 
-func nowUnix() int64 {
-	return time.Now().Unix()
+	return merged
 }
@@ -3,10 +3,13 @@ package search
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"upstonk/internal/domain"
 )
@@ -16,92 +19,157 @@ type AlphaVantageProvider struct {
 	apiKey     string
 	httpClient *http.Client
 	baseURL    string
+	universe   *Universe
+	quota      *QuotaTracker
+
+	mu       sync.RWMutex
+	profiles map[string]domain.ETF // Last fetched profile per ticker
 }
 
-func NewAlphaVantageProvider(apiKey string) *AlphaVantageProvider {
+const (
+	// alphaVantageMaxPerSearch caps how much of the budget one search may spend
+	alphaVantageMaxPerSearch = 10
+
+	// alphaVantageProfileTTL is how long a fetched profile is reused without spending budget
+	alphaVantageProfileTTL = 24 * time.Hour
+
+	// alphaVantageReserve is the share of the daily budget kept for priority tickers
+	alphaVantageReserve = 0.2
+)
+
+// NewAlphaVantageProvider creates an Alpha Vantage provider for the tickers in universe.
+// A nil httpClient uses a plain client with a 30 second timeout.
+func NewAlphaVantageProvider(apiKey string, httpClient *http.Client, universe *Universe) *AlphaVantageProvider {
 	if apiKey == "" {
 		apiKey = "demo" // Alpha Vantage provides a demo key
 	}
+	if httpClient == nil {
+		httpClient = NewHTTPClient(FixtureModeLive, "")
+	}
 
 	return &AlphaVantageProvider{
-		apiKey:  apiKey,
-		baseURL: "https://www.alphavantage.co/query",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:     apiKey,
+		baseURL:    "https://www.alphavantage.co/query",
+		httpClient: httpClient,
+		universe:   universe,
+		quota:      NewQuotaTracker("", 25, 5), // Free tier
+		profiles:   make(map[string]domain.ETF),
 	}
 }
 
-// Search implements the Provider interface
+// SetQuota replaces the request budget shared by all searches
+func (p *AlphaVantageProvider) SetQuota(quota *QuotaTracker) {
+	p.quota = quota
+}
+
+// QuotaStatus reports the remaining request budget
+func (p *AlphaVantageProvider) QuotaStatus() QuotaStatus {
+	return p.quota.Status()
+}
+
+// Search implements the Provider interface. Recently fetched profiles are
+// reused, priority tickers are fetched first, and once the daily or
+// per-minute budget runs out previously fetched profiles are returned instead
+// of waiting for a slot.
 func (p *AlphaVantageProvider) Search(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
-	// Get relevant tickers based on criteria
+	// Get relevant tickers based on criteria, most important first
 	tickers := p.getTickersForCriteria(criteria)
 
 	etfs := make([]domain.ETF, 0)
+	fetched := 0
+	exhausted := false
+	withheld := false // Budget left is reserved for priority tickers
+
+	for _, ticker := range tickers {
+		cached, fetchedAt, ok := p.cachedProfile(ticker)
+		if ok && time.Since(fetchedAt) < alphaVantageProfileTTL {
+			etfs = append(etfs, cached)
+			continue
+		}
 
-	// Fetch each ETF (limited by API rate limits)
-	for i, ticker := range tickers {
-		// Alpha Vantage free tier: 25 requests/day, so be conservative
-		if i >= 10 {
-			break
+		reserved := !exhausted && !p.canSpend(ticker)
+		withheld = withheld || reserved
+		if exhausted || reserved || fetched >= alphaVantageMaxPerSearch {
+			// Degrade to the last known profile rather than dropping the ticker
+			if ok {
+				etfs = append(etfs, cached)
+			}
+			continue
 		}
 
 		etf, err := p.GetETFProfile(ctx, ticker)
+		if errors.Is(err, ErrQuotaExhausted) {
+			log.Printf("Alpha Vantage budget unavailable, serving cached profiles: %v", err)
+			exhausted = true
+			if ok {
+				etfs = append(etfs, cached)
+			}
+			continue
+		}
+		fetched++
 		if err != nil {
+			if ok {
+				etfs = append(etfs, cached)
+			}
 			continue // Skip failed fetches
 		}
 
+		p.storeProfile(etf)
 		etfs = append(etfs, etf)
 	}
 
+	// Nothing to offer and no budget: let the caller treat this source as skipped
+	if (exhausted || withheld) && len(etfs) == 0 {
+		return nil, fmt.Errorf("alpha vantage: %w", ErrQuotaExhausted)
+	}
+
 	return etfs, nil
 }
 
-// getTickersForCriteria maps criteria to known ETF tickers
-func (p *AlphaVantageProvider) getTickersForCriteria(criteria Criteria) []string {
-	tickers := make([]string, 0)
-
-	// Map sectors to ETFs
-	for _, sector := range criteria.Sectors {
-		switch strings.ToLower(sector) {
-		case "technology":
-			tickers = append(tickers, "QQQ", "XLK", "VGT")
-		case "healthcare":
-			tickers = append(tickers, "XLV", "VHT")
-		case "financial", "financials":
-			tickers = append(tickers, "XLF", "VFH")
-		case "energy":
-			tickers = append(tickers, "XLE", "VDE")
-		}
+// canSpend reports whether budget may be used on ticker. The last
+// alphaVantageReserve share of the daily budget is kept for tickers the
+// universe ranks.
+func (p *AlphaVantageProvider) canSpend(ticker string) bool {
+	if p.universe.Priority(ticker) > 0 {
+		return true
 	}
 
-	// Map markets to ETFs
-	for _, market := range criteria.Markets {
-		switch strings.ToLower(market) {
-		case "usa", "us", "united states":
-			tickers = append(tickers, "SPY", "VOO", "VTI")
-		case "emerging", "emerging markets":
-			tickers = append(tickers, "EEM", "VWO")
-		case "international", "world":
-			tickers = append(tickers, "VEU", "VXUS")
-		case "europe":
-			tickers = append(tickers, "VGK", "EZU")
-		case "china":
-			tickers = append(tickers, "FXI", "MCHI")
-		}
+	remaining := p.quota.DailyRemaining()
+	if remaining < 0 {
+		return true
 	}
+	return float64(remaining) > float64(p.quota.dailyLimit)*alphaVantageReserve
+}
 
-	// Remove duplicates
-	seen := make(map[string]bool)
-	unique := make([]string, 0)
-	for _, ticker := range tickers {
-		if !seen[ticker] {
-			seen[ticker] = true
-			unique = append(unique, ticker)
-		}
-	}
+func (p *AlphaVantageProvider) cachedProfile(ticker string) (domain.ETF, time.Time, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	etf, ok := p.profiles[ticker]
+	return etf, etf.LastUpdated, ok
+}
+
+func (p *AlphaVantageProvider) storeProfile(etf domain.ETF) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profiles[etf.Ticker] = etf
+}
 
-	return unique
+// getTickersForCriteria returns the registry tickers Alpha Vantage can serve for criteria
+func (p *AlphaVantageProvider) getTickersForCriteria(criteria Criteria) []string {
+	entries := p.universe.Tickers(UniverseQuery{
+		Source:           "alpha_vantage",
+		ExcludeExchanges: []string{"JSE"},
+		Markets:          criteria.Markets,
+		Sectors:          criteria.Sectors,
+		Tickers:          criteria.Identifiers,
+	})
+
+	tickers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		tickers = append(tickers, entry.FetchSymbol())
+	}
+	return tickers
 }
 
 // GetETFProfile fetches detailed ETF information from Alpha Vantage
@@ -115,6 +183,7 @@ func (p *AlphaVantageProvider) GetETFProfile(ctx context.Context, ticker string)
 	// Parse numeric values from strings
 	ter, _ := strconv.ParseFloat(overview.NetExpenseRatio, 64)
 	aum, _ := strconv.ParseFloat(overview.NetAssets, 64)
+	dividendYield, _ := strconv.ParseFloat(overview.DividendYield, 64)
 
 	etf := domain.ETF{
 		Ticker:          ticker,
@@ -126,6 +195,7 @@ func (p *AlphaVantageProvider) GetETFProfile(ctx context.Context, ticker string)
 		Provider:        overview.FundFamily,
 		TER:             ter * 100, // Convert to percentage
 		AUM:             aum,
+		DividendYield:   dividendYield * 100, // Convert to percentage
 		InceptionDate:   p.parseDate(overview.InceptionDate),
 		IsLeveraged:     strings.ToUpper(overview.Leveraged) == "YES",
 		IsInverse:       false, // Not provided by Alpha Vantage
@@ -172,6 +242,10 @@ func (p *AlphaVantageProvider) fetchOverview(ctx context.Context, ticker string)
 	url := fmt.Sprintf("%s?function=ETF_PROFILE&symbol=%s&apikey=%s",
 		p.baseURL, ticker, p.apiKey)
 
+	if err := p.quota.TryAcquire(); err != nil {
+		return AlphaVantageOverview{}, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return AlphaVantageOverview{}, err
@@ -191,41 +265,8 @@ func (p *AlphaVantageProvider) fetchOverview(ctx context.Context, ticker string)
 	return result, nil
 }
 
-func (p *AlphaVantageProvider) fetchQuote(ctx context.Context, ticker string) (AlphaVantageQuote, error) {
-	url := fmt.Sprintf("%s?function=GLOBAL_QUOTE&symbol=%s&apikey=%s",
-		p.baseURL, ticker, p.apiKey)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return AlphaVantageQuote{}, err
-	}
-
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return AlphaVantageQuote{}, err
-	}
-	defer resp.Body.Close()
-
-	var result struct {
-		GlobalQuote AlphaVantageQuote `json:"Global Quote"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return AlphaVantageQuote{}, err
-	}
-
-	return result.GlobalQuote, nil
-}
-
 func (p *AlphaVantageProvider) getCountryFromExchange(exchange string) string {
-	exchangeMap := map[string]string{
-		"NYSE": "US", "NASDAQ": "US", "AMEX": "US",
-		"LSE": "GB",
-		"JSE": "ZA",
-		"TSE": "JP",
-		"FRA": "DE",
-	}
-
-	if country, ok := exchangeMap[exchange]; ok {
+	if country := domain.ExchangeCountry(exchange); country != "" {
 		return country
 	}
 	return "US" // Default
@@ -262,9 +303,3 @@ type AlphaVantageOverview struct {
 		Weight      string `json:"weight"` // String, needs conversion
 	} `json:"holdings"`
 }
-
-type AlphaVantageQuote struct {
-	Symbol string `json:"01. symbol"`
-	Price  string `json:"05. price"`
-	Volume int64  `json:"06. volume"`
-}
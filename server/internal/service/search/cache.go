@@ -0,0 +1,208 @@
+package search
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// ETFCache is a bounded in-memory LRU cache of search results. Entries are
+// fresh for ttl and may then be served stale, while a refresh runs, until
+// maxStaleness has passed since they were stored.
+type ETFCache struct {
+	mu           sync.Mutex
+	entries      map[string]*list.Element
+	order        *list.List // Front = most recently used
+	maxSize      int
+	ttl          time.Duration
+	maxStaleness time.Duration
+
+	hits      int64
+	staleHits int64
+	misses    int64
+	evictions int64
+}
+
+// CacheState classifies a cache lookup
+type CacheState int
+
+const (
+	CacheMiss  CacheState = iota // Not cached, or older than maxStaleness
+	CacheFresh                   // Within ttl
+	CacheStale                   // Past ttl but within maxStaleness
+)
+
+type cacheEntry struct {
+	key      string
+	etfs     []domain.ETF
+	storedAt time.Time
+}
+
+// CacheStats reports cache effectiveness counters
+type CacheStats struct {
+	Size         int     `json:"size"`
+	MaxSize      int     `json:"maxSize"`
+	TTL          string  `json:"ttl"`
+	MaxStaleness string  `json:"maxStaleness"`
+	Hits         int64   `json:"hits"`
+	StaleHits    int64   `json:"staleHits"`
+	Misses       int64   `json:"misses"`
+	Evictions    int64   `json:"evictions"`
+	HitRatio     float64 `json:"hitRatio"`
+}
+
+// NewETFCache creates a cache holding at most maxSize entries, fresh for ttl
+// and servable stale until maxStaleness. Non-positive values fall back to
+// 1000 entries and one hour; a maxStaleness below ttl disables stale serving.
+func NewETFCache(maxSize int, ttl, maxStaleness time.Duration) *ETFCache {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	if maxStaleness < ttl {
+		maxStaleness = ttl
+	}
+
+	return &ETFCache{
+		entries:      make(map[string]*list.Element),
+		order:        list.New(),
+		maxSize:      maxSize,
+		ttl:          ttl,
+		maxStaleness: maxStaleness,
+	}
+}
+
+// GenerateKey returns the cache key for criteria (see CriteriaKey)
+func (c *ETFCache) GenerateKey(criteria Criteria) string {
+	return CriteriaKey(criteria)
+}
+
+// CriteriaKey returns a canonical hash of the full criteria. Slice order,
+// letter case, surrounding whitespace and duplicates do not affect the key.
+func CriteriaKey(criteria Criteria) string {
+	canonical := struct {
+		Markets      []string `json:"markets"`
+		Sectors      []string `json:"sectors"`
+		AssetClasses []string `json:"assetClasses"`
+		Companies    []string `json:"companies"`
+		Country      string   `json:"country"`
+		Vehicles     []string `json:"vehicles"`
+		Exchanges    []string `json:"exchanges"`
+		Identifiers  []string `json:"identifiers,omitempty"`
+	}{
+		Markets:      canonicalList(criteria.Markets),
+		Sectors:      canonicalList(criteria.Sectors),
+		AssetClasses: canonicalList(criteria.AssetClasses),
+		Companies:    canonicalList(criteria.Companies),
+		Country:      strings.ToUpper(strings.TrimSpace(criteria.Country)),
+		Vehicles:     canonicalList(criteria.Vehicles),
+		Exchanges:    canonicalList(criteria.Exchanges),
+		Identifiers:  canonicalList(criteria.Identifiers),
+	}
+
+	// Marshalling a struct of strings cannot fail
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Lookup returns the cached results for key, when they were stored, and
+// whether they are fresh, stale or missing
+func (c *ETFCache) Lookup(key string) ([]domain.ETF, time.Time, CacheState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		c.misses++
+		return nil, time.Time{}, CacheMiss
+	}
+
+	entry := element.Value.(*cacheEntry)
+	age := time.Since(entry.storedAt)
+	if age > c.maxStaleness {
+		c.misses++
+		return nil, time.Time{}, CacheMiss
+	}
+
+	c.order.MoveToFront(element)
+	if age > c.ttl {
+		c.staleHits++
+		return entry.etfs, entry.storedAt, CacheStale
+	}
+
+	c.hits++
+	return entry.etfs, entry.storedAt, CacheFresh
+}
+
+func (c *ETFCache) Set(key string, etfs []domain.ETF) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*cacheEntry)
+		entry.etfs = etfs
+		entry.storedAt = time.Now()
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:      key,
+		etfs:     etfs,
+		storedAt: time.Now(),
+	})
+
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *ETFCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Size:         c.order.Len(),
+		MaxSize:      c.maxSize,
+		TTL:          c.ttl.String(),
+		MaxStaleness: c.maxStaleness.String(),
+		Hits:         c.hits,
+		StaleHits:    c.staleHits,
+		Misses:       c.misses,
+		Evictions:    c.evictions,
+	}
+	if lookups := c.hits + c.staleHits + c.misses; lookups > 0 {
+		stats.HitRatio = float64(c.hits+c.staleHits) / float64(lookups)
+	}
+	return stats
+}
+
+// canonicalList lower-cases, trims, de-duplicates and sorts a criteria list
+func canonicalList(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized := strings.ToLower(strings.TrimSpace(value))
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+	sort.Strings(result)
+	return result
+}
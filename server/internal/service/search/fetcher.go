@@ -0,0 +1,186 @@
+package search
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// FetchConfig bounds how LiveProvider fans out upstream requests
+type FetchConfig struct {
+	Workers        int           // Tickers fetched concurrently per search
+	PerHost        int           // Concurrent requests to any one host, across searches
+	MaxRetries     int           // Retries after a 429, 5xx or network error
+	BaseBackoff    time.Duration // First retry delay; doubles per attempt, with full jitter
+	DeadlineMargin time.Duration // Stop waiting this long before the deadline and return what has arrived
+}
+
+// DefaultFetchConfig fetches 8 tickers at a time, 4 per host, with 2 retries
+func DefaultFetchConfig() FetchConfig {
+	return FetchConfig{
+		Workers:        8,
+		PerHost:        4,
+		MaxRetries:     2,
+		BaseBackoff:    200 * time.Millisecond,
+		DeadlineMargin: time.Second,
+	}
+}
+
+// hostLimiter caps concurrent requests per host
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{limit: limit, slots: make(map[string]chan struct{})}
+}
+
+// acquire blocks until a slot for host is free and returns its release func
+func (h *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	h.mu.Lock()
+	slot, ok := h.slots[host]
+	if !ok {
+		slot = make(chan struct{}, h.limit)
+		h.slots[host] = slot
+	}
+	h.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// do sends a GET-style request under the host limit, retrying 429 and 5xx
+// responses and network errors with jittered exponential backoff
+func (p *LiveProvider) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		release, err := p.hosts.acquire(ctx, req.URL.Host)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := p.httpClient.Do(req.Clone(ctx))
+		release()
+
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt >= p.fetch.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		wait := p.backoff(attempt, resp)
+		if err != nil {
+			log.Printf("Retrying %s in %s: %v", req.URL.Host, wait, err)
+		} else {
+			log.Printf("Retrying %s in %s: status %d", req.URL.Host, wait, resp.StatusCode)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt: Retry-After when the
+// server sent one, otherwise a random delay up to BaseBackoff * 2^attempt
+func (p *LiveProvider) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	ceiling := p.fetch.BaseBackoff << attempt
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// fetchAll runs fetch for each ticker on a bounded worker pool. When the
+// context deadline is within DeadlineMargin it stops waiting and returns the
+// ETFs fetched so far, in ticker order.
+func (p *LiveProvider) fetchAll(ctx context.Context, tickers []string, fetch func(context.Context, string) (domain.ETF, error)) []domain.ETF {
+	if len(tickers) == 0 {
+		return nil
+	}
+
+	// Workers stop just before the caller's deadline so there is time to return
+	workCtx, cancel := ctx, context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		workCtx, cancel = context.WithDeadline(ctx, deadline.Add(-p.fetch.DeadlineMargin))
+	}
+	defer cancel()
+
+	type fetched struct {
+		index int
+		etf   domain.ETF
+		err   error
+	}
+
+	jobs := make(chan int)
+	results := make(chan fetched, len(tickers))
+
+	workers := min(p.fetch.Workers, len(tickers))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				etf, err := fetch(workCtx, tickers[i])
+				results <- fetched{index: i, etf: etf, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range tickers {
+			select {
+			case jobs <- i:
+			case <-workCtx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	found := make([]*domain.ETF, len(tickers))
+	for result := range results {
+		if result.err != nil {
+			log.Printf("Failed to fetch %s: %v", tickers[result.index], result.err)
+			continue
+		}
+		etf := result.etf
+		found[result.index] = &etf
+	}
+
+	etfs := make([]domain.ETF, 0, len(tickers))
+	for _, etf := range found {
+		if etf != nil {
+			etfs = append(etfs, *etf)
+		}
+	}
+
+	if workCtx.Err() != nil && ctx.Err() == nil {
+		log.Printf("Deadline approaching: returning partial results (%d of %d tickers fetched)", len(etfs), len(tickers))
+	}
+	return etfs
+}
@@ -0,0 +1,264 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"upstonk/internal/domain"
+)
+
+// ErrCircuitOpen is returned when a provider is skipped because its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerConfig controls per-provider timeouts and circuit breaking
+type BreakerConfig struct {
+	Timeout          time.Duration // Per-call limit, independent of the caller's deadline
+	FailureThreshold int           // Consecutive failures before the circuit opens
+	Cooldown         time.Duration // How long an open circuit skips the provider
+}
+
+// DefaultBreakerConfig allows 10s per call and opens after 5 failures for one minute
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Timeout:          10 * time.Second,
+		FailureThreshold: 5,
+		Cooldown:         time.Minute,
+	}
+}
+
+// CircuitState is the state of a provider's circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Calls flow normally
+	CircuitOpen     CircuitState = "open"      // Calls are skipped until the cooldown ends
+	CircuitHalfOpen CircuitState = "half_open" // One probe call decides whether to close again
+)
+
+// Source outcomes reported per search
+const (
+	SourceAnswered = "answered"
+	SourceFailed   = "failed"
+	SourceSkipped  = "skipped"
+)
+
+// SourceStatus reports what one data source did for a search
+type SourceStatus struct {
+	Name     string
+	Status   string // SourceAnswered, SourceFailed or SourceSkipped
+	Results  int
+	Duration time.Duration
+	Error    string
+}
+
+// ProviderHealth is a snapshot of a provider's health for the health endpoint
+type ProviderHealth struct {
+	Name                string  `json:"name"`
+	Circuit             string  `json:"circuit"`
+	Calls               int64   `json:"calls"`
+	Successes           int64   `json:"successes"`
+	Failures            int64   `json:"failures"`
+	Skipped             int64   `json:"skipped"`
+	SuccessRate         float64 `json:"successRate"`
+	AverageLatencyMs    int64   `json:"averageLatencyMs"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+	LastError           string  `json:"lastError,omitempty"`
+	LastErrorAt         string  `json:"lastErrorAt,omitempty"`
+	OpenUntil           string  `json:"openUntil,omitempty"`
+
+	Quota *QuotaStatus `json:"quota,omitempty"` // Remaining request budget, for rate-limited APIs
+}
+
+// MonitoredProvider wraps a Provider with health tracking, a per-call
+// timeout and a circuit breaker
+type MonitoredProvider struct {
+	name     string
+	provider Provider
+	config   BreakerConfig
+
+	mu                  sync.Mutex
+	state               CircuitState
+	openedAt            time.Time
+	probing             bool
+	calls               int64
+	successes           int64
+	failures            int64
+	skipped             int64
+	totalLatency        time.Duration
+	consecutiveFailures int
+	lastError           string
+	lastErrorAt         time.Time
+}
+
+// NewMonitoredProvider wraps provider under the given name. Zero config
+// fields fall back to DefaultBreakerConfig.
+func NewMonitoredProvider(name string, provider Provider, config BreakerConfig) *MonitoredProvider {
+	defaults := DefaultBreakerConfig()
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+
+	return &MonitoredProvider{
+		name:     name,
+		provider: provider,
+		config:   config,
+		state:    CircuitClosed,
+	}
+}
+
+// Name identifies the provider in summaries and health reports
+func (m *MonitoredProvider) Name() string {
+	return m.name
+}
+
+// Search implements the Provider interface. It returns ErrCircuitOpen without
+// calling the provider while the circuit is open.
+func (m *MonitoredProvider) Search(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
+	if !m.allow() {
+		return nil, fmt.Errorf("%s: %w", m.name, ErrCircuitOpen)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	etfs, err := m.provider.Search(callCtx, criteria)
+	elapsed := time.Since(start)
+
+	// The caller giving up, or a spent request budget, says nothing about the provider's health
+	if err != nil && (ctx.Err() != nil || errors.Is(err, ErrQuotaExhausted)) {
+		m.release()
+		return nil, err
+	}
+
+	m.record(elapsed, err)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.name, err)
+	}
+	return etfs, nil
+}
+
+// allow reports whether a call may proceed, moving an expired open circuit to half-open
+func (m *MonitoredProvider) allow() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch m.state {
+	case CircuitOpen:
+		if time.Since(m.openedAt) < m.config.Cooldown {
+			m.skipped++
+			return false
+		}
+		m.state = CircuitHalfOpen
+		m.probing = true
+		return true
+	case CircuitHalfOpen:
+		// Only one probe at a time
+		if m.probing {
+			m.skipped++
+			return false
+		}
+		m.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// release ends a probe without recording an outcome
+func (m *MonitoredProvider) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.probing = false
+}
+
+func (m *MonitoredProvider) record(elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+	m.totalLatency += elapsed
+	m.probing = false
+
+	if err == nil {
+		m.successes++
+		m.consecutiveFailures = 0
+		m.state = CircuitClosed
+		return
+	}
+
+	m.failures++
+	m.consecutiveFailures++
+	m.lastError = err.Error()
+	m.lastErrorAt = time.Now()
+
+	if m.state == CircuitHalfOpen || m.consecutiveFailures >= m.config.FailureThreshold {
+		m.state = CircuitOpen
+		m.openedAt = time.Now()
+	}
+}
+
+// Health returns a snapshot of the provider's health
+func (m *MonitoredProvider) Health() ProviderHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health := ProviderHealth{
+		Name:                m.name,
+		Circuit:             string(m.state),
+		Calls:               m.calls,
+		Successes:           m.successes,
+		Failures:            m.failures,
+		Skipped:             m.skipped,
+		ConsecutiveFailures: m.consecutiveFailures,
+		LastError:           m.lastError,
+	}
+	if m.calls > 0 {
+		health.SuccessRate = float64(m.successes) / float64(m.calls)
+		health.AverageLatencyMs = (m.totalLatency / time.Duration(m.calls)).Milliseconds()
+	}
+	if !m.lastErrorAt.IsZero() {
+		health.LastErrorAt = m.lastErrorAt.UTC().Format(time.RFC3339)
+	}
+	if m.state == CircuitOpen {
+		health.OpenUntil = m.openedAt.Add(m.config.Cooldown).UTC().Format(time.RFC3339)
+	}
+	if limited, ok := m.provider.(interface{ QuotaStatus() QuotaStatus }); ok {
+		quota := limited.QuotaStatus()
+		health.Quota = &quota
+	}
+	return health
+}
+
+// providerName returns the name a provider reports, or its type
+func providerName(provider Provider) string {
+	if named, ok := provider.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", provider)
+}
+
+// sourceStatus classifies a provider call for the search summary
+func sourceStatus(name string, results int, elapsed time.Duration, err error) SourceStatus {
+	status := SourceStatus{Name: name, Results: results, Duration: elapsed}
+	switch {
+	case err == nil:
+		status.Status = SourceAnswered
+	case errors.Is(err, ErrCircuitOpen), errors.Is(err, ErrQuotaExhausted):
+		status.Status = SourceSkipped
+		status.Error = err.Error()
+	default:
+		status.Status = SourceFailed
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -3,6 +3,8 @@ package search
 
 import (
 	"context"
+	"strings"
+
 	"upstonk/internal/domain"
 )
 
@@ -11,6 +13,20 @@ type Provider interface {
 	Search(ctx context.Context, criteria Criteria) ([]domain.ETF, error)
 }
 
+// localListingCountries are investor countries whose searches only return
+// ETFs listed on their own exchanges: South African investors get JSE listings
+var localListingCountries = map[string]bool{"ZA": true}
+
+// ListingCountry returns the exchange country a search for an investor in
+// country is limited to, or "" when listings anywhere qualify
+func ListingCountry(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if localListingCountries[country] {
+		return country
+	}
+	return ""
+}
+
 type Criteria struct {
 	Markets      []string
 	Sectors      []string
@@ -18,4 +34,6 @@ type Criteria struct {
 	Companies    []string
 	Country      string
 	Vehicles     []string
+	Exchanges    []string
+	Identifiers  []string // Tickers or ISINs to look up directly; other filters are ignored
 }
@@ -0,0 +1,342 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// MergePolicy controls how records for the same ETF from different providers are combined
+type MergePolicy struct {
+	// Precedence lists DataSource.Reliability values from most to least trusted.
+	// Unlisted reliabilities rank after all listed ones.
+	Precedence []string
+
+	// NumericTolerance is the relative difference (0.05 = 5%) above which
+	// numeric values from different sources are reported as a conflict
+	NumericTolerance float64
+}
+
+// DefaultMergePolicy trusts Primary over Secondary over Tertiary sources
+func DefaultMergePolicy() MergePolicy {
+	return MergePolicy{
+		Precedence:       []string{"Primary", "Secondary", "Tertiary"},
+		NumericTolerance: 0.05,
+	}
+}
+
+// sourcedRecord is one provider's view of an ETF
+type sourcedRecord struct {
+	etf    domain.ETF
+	source domain.DataSource
+}
+
+// fieldSource is the source of one field: its recorded provenance when the
+// record is itself a merge, otherwise the record's source
+func (r sourcedRecord) fieldSource(field string) domain.DataSource {
+	if source, ok := r.etf.FieldProvenance[field]; ok {
+		return source
+	}
+	return r.source
+}
+
+// Merge combines records for one ETF. For each field the value from the
+// highest-precedence source that supplied it wins; its source is stored in
+// FieldProvenance and disagreements are listed in DataConflicts. Records that
+// are themselves merges are ranked per field by their FieldProvenance.
+func (p MergePolicy) Merge(etfs ...domain.ETF) domain.ETF {
+	records := make([]sourcedRecord, 0, len(etfs))
+	for _, etf := range etfs {
+		records = append(records, sourcedRecord{etf: etf, source: primarySource(etf)})
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return p.before(records[i].source, records[j].source)
+	})
+
+	m := &merger{
+		policy:     p,
+		records:    records,
+		merged:     records[0].etf,
+		provenance: make(map[string]domain.DataSource),
+	}
+
+	m.text("name", func(e domain.ETF) string { return e.Name }, func(v string) { m.merged.Name = v })
+	m.text("isin", func(e domain.ETF) string { return e.ISIN }, func(v string) { m.merged.ISIN = v })
+	m.text("exchange", func(e domain.ETF) string { return e.Exchange }, func(v string) { m.merged.Exchange = v })
+	m.text("exchangeCountry", func(e domain.ETF) string {
+		if e.ExchangeCountry == "UNKNOWN" {
+			return ""
+		}
+		return e.ExchangeCountry
+	}, func(v string) { m.merged.ExchangeCountry = v })
+	m.text("domicile", func(e domain.ETF) string { return e.Domicile }, func(v string) { m.merged.Domicile = v })
+	m.text("legalStructure", func(e domain.ETF) string { return e.LegalStructure }, func(v string) { m.merged.LegalStructure = v })
+	m.text("replicationMethod", func(e domain.ETF) string { return e.ReplicationMethod }, func(v string) { m.merged.ReplicationMethod = v })
+	m.text("assetClass", func(e domain.ETF) string { return e.AssetClass }, func(v string) { m.merged.AssetClass = v })
+	m.text("trackingIndex", func(e domain.ETF) string { return e.TrackingIndex }, func(v string) { m.merged.TrackingIndex = v })
+	m.text("currency", func(e domain.ETF) string { return e.Currency }, func(v string) { m.merged.Currency = v })
+	m.text("dividendTreatment", func(e domain.ETF) string { return e.DividendTreatment }, func(v string) { m.merged.DividendTreatment = v })
+	m.text("provider", func(e domain.ETF) string { return e.Provider }, func(v string) { m.merged.Provider = v })
+
+	m.number("ter", func(e domain.ETF) float64 { return e.TER }, func(v float64) { m.merged.TER = v })
+	m.number("trackingDifference", func(e domain.ETF) float64 { return e.TrackingDifference }, func(v float64) { m.merged.TrackingDifference = v })
+	m.number("aum", func(e domain.ETF) float64 { return e.AUM }, func(v float64) { m.merged.AUM = v })
+	m.number("averageDailyVolume", func(e domain.ETF) float64 { return e.AverageDailyVolume }, func(v float64) { m.merged.AverageDailyVolume = v })
+	m.number("dividendYield", func(e domain.ETF) float64 { return e.DividendYield }, func(v float64) { m.merged.DividendYield = v })
+	m.number("bidAskSpread", func(e domain.ETF) float64 { return e.BidAskSpread }, func(v float64) { m.merged.BidAskSpread = v })
+
+	// Structural flags are compliance inputs: any source asserting them wins
+	m.flag("isLeveraged", func(e domain.ETF) bool { return e.IsLeveraged }, func(v bool) { m.merged.IsLeveraged = v })
+	m.flag("isInverse", func(e domain.ETF) bool { return e.IsInverse }, func(v bool) { m.merged.IsInverse = v })
+	m.flag("isSynthetic", func(e domain.ETF) bool { return e.IsSynthetic }, func(v bool) { m.merged.IsSynthetic = v })
+	m.flag("isPhysical", func(e domain.ETF) bool { return e.IsPhysical }, func(v bool) { m.merged.IsPhysical = v })
+	m.merged.IsPhysical = m.merged.IsPhysical && !m.merged.IsSynthetic
+
+	m.collections()
+
+	// Combine data sources
+	allSources := make([]domain.DataSource, 0)
+	for _, etf := range etfs {
+		allSources = append(allSources, etf.DataSources...)
+	}
+	m.merged.DataSources = allSources
+	m.merged.FieldProvenance = m.provenance
+	m.merged.DataConflicts = m.conflicts
+
+	return m.merged
+}
+
+// rank returns the precedence position of a reliability level (lower is better)
+func (p MergePolicy) rank(reliability string) int {
+	for i, level := range p.Precedence {
+		if strings.EqualFold(strings.TrimSpace(level), reliability) {
+			return i
+		}
+	}
+	return len(p.Precedence)
+}
+
+// before reports whether source a takes precedence over b: higher reliability,
+// then curated (Manual) data, then fresher data
+func (p MergePolicy) before(a, b domain.DataSource) bool {
+	if ra, rb := p.rank(a.Reliability), p.rank(b.Reliability); ra != rb {
+		return ra < rb
+	}
+	if manualA, manualB := a.Type == "Manual", b.Type == "Manual"; manualA != manualB {
+		return manualA
+	}
+	return a.AccessDate.After(b.AccessDate)
+}
+
+type merger struct {
+	policy     MergePolicy
+	records    []sourcedRecord // Sorted by precedence
+	merged     domain.ETF
+	provenance map[string]domain.DataSource
+	conflicts  []domain.DataConflict
+}
+
+// ordered returns the records with the source of field, sorted by its precedence
+func (m *merger) ordered(field string) []sourcedRecord {
+	records := make([]sourcedRecord, len(m.records))
+	for i, record := range m.records {
+		records[i] = sourcedRecord{etf: record.etf, source: record.fieldSource(field)}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return m.policy.before(records[i].source, records[j].source)
+	})
+	return records
+}
+
+func (m *merger) text(field string, get func(domain.ETF) string, set func(string)) {
+	records := m.ordered(field)
+	values := make([]domain.SourcedValue, 0, len(records))
+	var winner *sourcedRecord
+	distinct := make(map[string]bool)
+
+	for i := range records {
+		value := strings.TrimSpace(get(records[i].etf))
+		if value == "" {
+			continue
+		}
+		if winner == nil {
+			winner = &records[i]
+			set(value)
+		}
+		distinct[strings.ToLower(value)] = true
+		values = append(values, sourcedValue(records[i].source, value))
+	}
+
+	m.record(field, winner, values, len(distinct) > 1)
+}
+
+func (m *merger) number(field string, get func(domain.ETF) float64, set func(float64)) {
+	records := m.ordered(field)
+	values := make([]domain.SourcedValue, 0, len(records))
+	var winner *sourcedRecord
+	conflict := false
+
+	for i := range records {
+		value := get(records[i].etf)
+		if value == 0 {
+			continue
+		}
+		if winner == nil {
+			winner = &records[i]
+			set(value)
+		} else if relativeDifference(get(winner.etf), value) > m.policy.NumericTolerance {
+			conflict = true
+		}
+		values = append(values, sourcedValue(records[i].source, strconv.FormatFloat(value, 'f', -1, 64)))
+	}
+
+	m.record(field, winner, values, conflict)
+}
+
+// flag sets a structural flag when any source asserts it. A source leaving a
+// flag unset cannot be told apart from one not reporting it, so it never
+// clears a flag another source set.
+func (m *merger) flag(field string, get func(domain.ETF) bool, set func(bool)) {
+	records := m.ordered(field)
+	values := make([]domain.SourcedValue, 0, len(records))
+	var winner *sourcedRecord
+	seenTrue, seenFalse := false, false
+
+	for i := range records {
+		value := get(records[i].etf)
+		if value && !seenTrue {
+			winner = &records[i]
+		}
+		seenTrue = seenTrue || value
+		seenFalse = seenFalse || !value
+		values = append(values, sourcedValue(records[i].source, strconv.FormatBool(value)))
+	}
+
+	set(seenTrue)
+	if winner == nil {
+		winner = &records[0]
+	}
+	m.record(field, winner, values, seenTrue && seenFalse)
+}
+
+// collections merges list/map fields, preferring the most detailed source
+func (m *merger) collections() {
+	var holdingsFrom, sectorsFrom *sourcedRecord
+	m.merged.TopHoldings = nil
+	m.merged.SectorExposure = nil
+	m.merged.GeographicExposure = domain.GeographicExposure{}
+
+	for i := range m.records {
+		etf := m.records[i].etf
+
+		// Merge holdings (prefer longer list)
+		if len(etf.TopHoldings) > len(m.merged.TopHoldings) {
+			m.merged.TopHoldings = etf.TopHoldings
+			holdingsFrom = &m.records[i]
+		}
+
+		// Merge sector exposure (prefer more detailed)
+		if len(etf.SectorExposure) > len(m.merged.SectorExposure) {
+			m.merged.SectorExposure = etf.SectorExposure
+			sectorsFrom = &m.records[i]
+		}
+
+		// Merge geographic exposure (higher-precedence weights win per region/country)
+		for region, weight := range etf.GeographicExposure.Regions {
+			if _, exists := m.merged.GeographicExposure.Regions[region]; !exists {
+				if m.merged.GeographicExposure.Regions == nil {
+					m.merged.GeographicExposure.Regions = make(map[string]float64)
+				}
+				m.merged.GeographicExposure.Regions[region] = weight
+				m.setProvenance("geographicExposure", m.records[i].source)
+			}
+		}
+		for country, weight := range etf.GeographicExposure.Countries {
+			if _, exists := m.merged.GeographicExposure.Countries[country]; !exists {
+				if m.merged.GeographicExposure.Countries == nil {
+					m.merged.GeographicExposure.Countries = make(map[string]float64)
+				}
+				m.merged.GeographicExposure.Countries[country] = weight
+				m.setProvenance("geographicExposure", m.records[i].source)
+			}
+		}
+
+		if m.merged.AssetExposure == (domain.AssetExposure{}) && etf.AssetExposure != (domain.AssetExposure{}) {
+			m.merged.AssetExposure = etf.AssetExposure
+			m.setProvenance("assetExposure", m.records[i].source)
+		}
+		if m.merged.InceptionDate.IsZero() && !etf.InceptionDate.IsZero() {
+			m.merged.InceptionDate = etf.InceptionDate
+		}
+		if etf.LastUpdated.After(m.merged.LastUpdated) {
+			m.merged.LastUpdated = etf.LastUpdated
+		}
+	}
+
+	if holdingsFrom != nil {
+		m.setProvenance("topHoldings", holdingsFrom.source)
+	}
+	if sectorsFrom != nil {
+		m.setProvenance("sectorExposure", sectorsFrom.source)
+	}
+}
+
+func (m *merger) record(field string, winner *sourcedRecord, values []domain.SourcedValue, conflict bool) {
+	if winner == nil {
+		return
+	}
+	m.provenance[field] = winner.source
+
+	if conflict {
+		m.conflicts = append(m.conflicts, domain.DataConflict{
+			Field:    field,
+			Values:   values,
+			Selected: winner.source.Provider,
+		})
+	}
+}
+
+func (m *merger) setProvenance(field string, source domain.DataSource) {
+	if _, exists := m.provenance[field]; !exists {
+		m.provenance[field] = source
+	}
+}
+
+// primarySource is the source that produced a provider's record: its first
+// DataSource (later entries are supplementary, e.g. an exchange listing)
+func primarySource(etf domain.ETF) domain.DataSource {
+	if len(etf.DataSources) == 0 {
+		return domain.DataSource{Provider: "unknown"}
+	}
+	return etf.DataSources[0]
+}
+
+func sourcedValue(source domain.DataSource, value string) domain.SourcedValue {
+	return domain.SourcedValue{
+		Provider:    source.Provider,
+		Reliability: source.Reliability,
+		Value:       value,
+	}
+}
+
+func relativeDifference(a, b float64) float64 {
+	scale := math.Max(math.Abs(a), math.Abs(b))
+	if scale == 0 {
+		return 0
+	}
+	return math.Abs(a-b) / scale
+}
+
+// FormatConflict renders a conflict for warnings, e.g. "ter: Yahoo Finance=0.2, Alpha Vantage=0.35"
+func FormatConflict(conflict domain.DataConflict) string {
+	parts := make([]string, 0, len(conflict.Values))
+	for _, value := range conflict.Values {
+		parts = append(parts, fmt.Sprintf("%s=%s", value.Provider, value.Value))
+	}
+	return fmt.Sprintf("%s: %s (using %s)", conflict.Field, strings.Join(parts, ", "), conflict.Selected)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 	"upstonk/internal/domain"
 )
@@ -16,18 +17,60 @@ import (
 type LiveProvider struct {
 	httpClient *http.Client
 	userAgent  string
+	universe   *Universe
+	fetch      FetchConfig
+	hosts      *hostLimiter
 }
 
-func NewLiveProvider() Provider {
+// NewLiveProvider creates a provider backed by Yahoo Finance, ETF.com and JSE
+// that fetches the tickers listed in universe.
+// A nil httpClient uses a plain client with a 30 second timeout.
+func NewLiveProvider(httpClient *http.Client, universe *Universe) *LiveProvider {
+	if httpClient == nil {
+		httpClient = NewHTTPClient(FixtureModeLive, "")
+	}
+
+	fetch := DefaultFetchConfig()
 	return &LiveProvider{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		userAgent: "Mozilla/5.0 (compatible; ETFDiscoveryBot/1.0)",
+		httpClient: httpClient,
+		userAgent:  "Mozilla/5.0 (compatible; ETFDiscoveryBot/1.0)",
+		universe:   universe,
+		fetch:      fetch,
+		hosts:      newHostLimiter(fetch.PerHost),
+	}
+}
+
+// SetFetchConfig replaces the concurrency, retry and deadline settings.
+// Zero fields keep their defaults; a negative MaxRetries disables retries.
+func (p *LiveProvider) SetFetchConfig(config FetchConfig) {
+	defaults := DefaultFetchConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
 	}
+	if config.PerHost <= 0 {
+		config.PerHost = defaults.PerHost
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaults.MaxRetries
+	} else if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.DeadlineMargin <= 0 {
+		config.DeadlineMargin = defaults.DeadlineMargin
+	}
+
+	p.fetch = config
+	p.hosts = newHostLimiter(config.PerHost)
 }
 
 func (p *LiveProvider) Search(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
+	if len(criteria.Identifiers) > 0 {
+		return p.lookup(ctx, criteria), nil
+	}
+
 	etfs := make([]domain.ETF, 0)
 
 	// Strategy: Search multiple sources and aggregate results
@@ -45,17 +88,27 @@ func (p *LiveProvider) Search(ctx context.Context, criteria Criteria) ([]domain.
 	}
 
 	// 2. For ZA country, don't search global ETFs - only JSE-listed ETFs are eligible
-	// Only search global sources if not ZA
-	if criteria.Country != "ZA" {
-		// Search ETF.com API for global ETFs
-		globalETFs, err := p.searchETFDotCom(ctx, criteria)
-		if err == nil {
+	// Only search global sources for countries without a local listing scope
+	if ListingCountry(criteria.Country) == "" {
+		// Search ETF.com and Yahoo Finance concurrently
+		var globalETFs, yahooETFs []domain.ETF
+		var globalErr, yahooErr error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			globalETFs, globalErr = p.searchETFDotCom(ctx, criteria)
+		}()
+		go func() {
+			defer wg.Done()
+			yahooETFs, yahooErr = p.searchYahooFinance(ctx, criteria)
+		}()
+		wg.Wait()
+
+		if globalErr == nil {
 			etfs = append(etfs, globalETFs...)
 		}
-
-		// Search Yahoo Finance for ETF data
-		yahooETFs, err := p.searchYahooFinance(ctx, criteria)
-		if err == nil {
+		if yahooErr == nil {
 			etfs = append(etfs, yahooETFs...)
 		}
 	}
@@ -78,13 +131,37 @@ func (p *LiveProvider) Search(ctx context.Context, criteria Criteria) ([]domain.
 	return filtered, nil
 }
 
+// lookup fetches the requested registry tickers wherever they are listed,
+// regardless of the investor's country
+func (p *LiveProvider) lookup(ctx context.Context, criteria Criteria) []domain.ETF {
+	jseETFs, err := p.searchJSE(ctx, criteria)
+	if err != nil {
+		log.Printf("JSE lookup error: %v", err)
+	}
+	globalETFs, err := p.searchYahooFinance(ctx, criteria)
+	if err != nil {
+		log.Printf("Yahoo Finance lookup error: %v", err)
+	}
+
+	etfs := p.deduplicate(append(jseETFs, globalETFs...))
+	log.Printf("Lookup of %v found %d ETFs", criteria.Identifiers, len(etfs))
+	return etfs
+}
+
 // searchJSE searches for JSE-listed ETFs using Yahoo Finance
 // JSE ETFs on Yahoo Finance use .JO suffix (e.g., STXEMG.JO)
 func (p *LiveProvider) searchJSE(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
 	etfs := make([]domain.ETF, 0)
 
 	// Get JSE ETF tickers based on criteria
-	jseTickers := p.getJSETickersForCriteria(criteria)
+	jseTickers := p.universeTickers(UniverseQuery{
+		Source:       "yahoo",
+		Exchanges:    []string{"JSE"},
+		Markets:      criteria.Markets,
+		AssetClasses: criteria.AssetClasses,
+		Tickers:      criteria.Identifiers,
+		Fallback:     true,
+	})
 	log.Printf("Searching for JSE ETFs with tickers: %v", jseTickers)
 
 	if len(jseTickers) == 0 {
@@ -92,17 +169,13 @@ func (p *LiveProvider) searchJSE(ctx context.Context, criteria Criteria) ([]doma
 		return etfs, nil
 	}
 
-	// Fetch each JSE ETF from Yahoo Finance
-	for _, ticker := range jseTickers {
-		// Add .JO suffix for JSE listings on Yahoo Finance
-		yahooTicker := ticker + ".JO"
-		log.Printf("Fetching JSE ETF: %s (Yahoo ticker: %s)", ticker, yahooTicker)
+	// Fetch JSE ETFs from Yahoo Finance concurrently
+	etfs = p.fetchAll(ctx, jseTickers, func(ctx context.Context, yahooTicker string) (domain.ETF, error) {
+		log.Printf("Fetching JSE ETF: %s", yahooTicker)
 
 		etf, err := p.fetchYahooFinanceETF(ctx, yahooTicker)
 		if err != nil {
-			log.Printf("Failed to fetch %s: %v", yahooTicker, err)
-			// Skip failed fetches, continue with others
-			continue
+			return domain.ETF{}, err
 		}
 
 		// Ensure exchange info is set correctly for JSE (override any Yahoo Finance data)
@@ -122,13 +195,23 @@ func (p *LiveProvider) searchJSE(ctx context.Context, criteria Criteria) ([]doma
 		})
 
 		log.Printf("Successfully fetched JSE ETF: %s (%s) - Exchange: %s, Currency: %s", etf.Ticker, etf.Name, etf.Exchange, etf.Currency)
-		etfs = append(etfs, etf)
-	}
+		return etf, nil
+	})
 
 	log.Printf("JSE search completed: found %d ETFs", len(etfs))
 	return etfs, nil
 }
 
+// universeTickers returns the upstream symbols for the registry entries matching query
+func (p *LiveProvider) universeTickers(query UniverseQuery) []string {
+	entries := p.universe.Tickers(query)
+	tickers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		tickers = append(tickers, entry.FetchSymbol())
+	}
+	return tickers
+}
+
 // searchETFDotCom searches ETF.com's public API
 func (p *LiveProvider) searchETFDotCom(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
 	// Build search query
@@ -144,7 +227,7 @@ func (p *LiveProvider) searchETFDotCom(ctx context.Context, criteria Criteria) (
 	req.Header.Set("User-Agent", p.userAgent)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -164,20 +247,18 @@ func (p *LiveProvider) searchETFDotCom(ctx context.Context, criteria Criteria) (
 
 // searchYahooFinance uses Yahoo Finance API
 func (p *LiveProvider) searchYahooFinance(ctx context.Context, criteria Criteria) ([]domain.ETF, error) {
-	etfs := make([]domain.ETF, 0)
-
 	// Get common ETF tickers based on criteria
-	tickers := p.getETFTickersForCriteria(criteria)
-
-	for _, ticker := range tickers {
-		etf, err := p.fetchYahooFinanceETF(ctx, ticker)
-		if err != nil {
-			continue // Skip failed fetches
-		}
-		etfs = append(etfs, etf)
-	}
-
-	return etfs, nil
+	tickers := p.universeTickers(UniverseQuery{
+		Source:           "yahoo",
+		ExcludeExchanges: []string{"JSE"},
+		Markets:          criteria.Markets,
+		Sectors:          criteria.Sectors,
+		AssetClasses:     criteria.AssetClasses,
+		Tickers:          criteria.Identifiers,
+		Fallback:         true,
+	})
+
+	return p.fetchAll(ctx, tickers, p.fetchYahooFinanceETF), nil
 }
 
 // fetchYahooFinanceETF fetches a single ETF from Yahoo Finance
@@ -191,7 +272,14 @@ func (p *LiveProvider) fetchYahooFinanceETF(ctx context.Context, ticker string)
 	}
 	req.Header.Set("User-Agent", p.userAgent)
 
-	resp, err := p.httpClient.Do(req)
+	// The detail request does not depend on the quote, so run both at once
+	detailsDone := make(chan ETFDetails, 1)
+	go func() {
+		details, _ := p.fetchYahooETFDetails(ctx, ticker)
+		detailsDone <- details
+	}()
+
+	resp, err := p.do(req)
 	if err != nil {
 		return domain.ETF{}, err
 	}
@@ -208,8 +296,8 @@ func (p *LiveProvider) fetchYahooFinanceETF(ctx context.Context, ticker string)
 
 	quote := result.QuoteResponse.Result[0]
 
-	// Fetch additional details from Yahoo Finance
-	details, _ := p.fetchYahooETFDetails(ctx, ticker)
+	// Additional details from Yahoo Finance
+	details := <-detailsDone
 
 	// Extract base ticker (remove exchange suffix like .JO)
 	baseTicker := quote.Symbol
@@ -271,7 +359,7 @@ func (p *LiveProvider) fetchYahooETFDetails(ctx context.Context, ticker string)
 	}
 	req.Header.Set("User-Agent", p.userAgent)
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req)
 	if err != nil {
 		return ETFDetails{}, err
 	}
@@ -285,72 +373,6 @@ func (p *LiveProvider) fetchYahooETFDetails(ctx context.Context, ticker string)
 	return p.parseYahooDetails(result), nil
 }
 
-// getJSETickersForCriteria returns JSE ETF tickers based on search criteria
-func (p *LiveProvider) getJSETickersForCriteria(criteria Criteria) []string {
-	tickers := make([]string, 0)
-
-	// Map markets to JSE ETF tickers
-	for _, market := range criteria.Markets {
-		marketLower := strings.ToLower(market)
-
-		// Check for emerging markets, China, India first (most specific)
-		if strings.Contains(marketLower, "emerging") || strings.Contains(marketLower, "china") || strings.Contains(marketLower, "india") {
-			// JSE ETFs for emerging markets exposure
-			tickers = append(tickers, "STXEMG") // Satrix MSCI Emerging Markets
-			tickers = append(tickers, "COREEM") // CoreShares MSCI Emerging Markets
-		}
-
-		// Check for Africa/South Africa
-		if strings.Contains(marketLower, "africa") || strings.Contains(marketLower, "south africa") {
-			// JSE ETFs for South African exposure
-			tickers = append(tickers, "STX40")  // Satrix Top 40 (SA equity)
-			tickers = append(tickers, "STXRES") // Satrix RESI 10
-		}
-
-		// Check for US markets
-		if strings.Contains(marketLower, "usa") || strings.Contains(marketLower, "us") || strings.Contains(marketLower, "united states") {
-			// JSE ETFs for US exposure
-			tickers = append(tickers, "STXNDQ") // Satrix NASDAQ 100
-			tickers = append(tickers, "STX500") // Satrix S&P 500
-		}
-
-		// Check for world/global markets
-		if strings.Contains(marketLower, "world") || strings.Contains(marketLower, "global") {
-			// JSE ETFs for global exposure
-			tickers = append(tickers, "STXWDM") // Satrix MSCI World
-		}
-
-		// Check for European markets
-		if strings.Contains(marketLower, "europe") {
-			// JSE ETFs for European exposure
-			tickers = append(tickers, "STXEUR") // Satrix MSCI Europe
-		}
-	}
-
-	// Map asset classes
-	for _, assetClass := range criteria.AssetClasses {
-		assetLower := strings.ToLower(assetClass)
-		if strings.Contains(assetLower, "equity") {
-			// Add general equity ETFs if not already added
-			if len(tickers) == 0 {
-				tickers = append(tickers, "STX40") // Satrix Top 40 (SA equity)
-			}
-		}
-	}
-
-	// Remove duplicates
-	seen := make(map[string]bool)
-	unique := make([]string, 0)
-	for _, ticker := range tickers {
-		if !seen[ticker] {
-			seen[ticker] = true
-			unique = append(unique, ticker)
-		}
-	}
-
-	return unique
-}
-
 // Helper: Build search query from criteria
 func (p *LiveProvider) buildSearchQuery(criteria Criteria) string {
 	parts := make([]string, 0)
@@ -366,81 +388,9 @@ func (p *LiveProvider) buildSearchQuery(criteria Criteria) string {
 	return strings.Join(parts, " ")
 }
 
-// Helper: Get ETF tickers based on criteria (for non-JSE ETFs)
-func (p *LiveProvider) getETFTickersForCriteria(criteria Criteria) []string {
-	tickers := make([]string, 0)
-
-	// Map sectors/markets to known ETFs
-	if contains(criteria.Sectors, "technology") {
-		tickers = append(tickers, "QQQ", "XLK", "VGT", "SOXX")
-	}
-
-	if contains(criteria.Markets, "usa") || contains(criteria.Markets, "us") {
-		tickers = append(tickers, "SPY", "VOO", "IVV", "VTI")
-	}
-
-	if contains(criteria.Markets, "emerging") || contains(criteria.Markets, "emerging markets") {
-		tickers = append(tickers, "EEM", "VWO", "IEMG")
-	}
-
-	if contains(criteria.Markets, "china") {
-		tickers = append(tickers, "FXI", "MCHI", "ASHR")
-	}
-
-	if contains(criteria.Markets, "india") {
-		tickers = append(tickers, "INDA", "EPI", "INDY")
-	}
-
-	if contains(criteria.Sectors, "healthcare") {
-		tickers = append(tickers, "XLV", "VHT", "IHI")
-	}
-
-	// If no specific criteria matched, add popular ETFs based on asset class
-	if len(tickers) == 0 {
-		for _, assetClass := range criteria.AssetClasses {
-			assetLower := strings.ToLower(assetClass)
-			if strings.Contains(assetLower, "equity") {
-				// Popular equity ETFs
-				tickers = append(tickers, "SPY", "VOO", "VTI", "QQQ", "IVV")
-			} else if strings.Contains(assetLower, "bond") {
-				// Popular bond ETFs
-				tickers = append(tickers, "AGG", "BND", "TLT", "LQD")
-			}
-		}
-	}
-
-	// If still no tickers, add some default popular ETFs
-	if len(tickers) == 0 {
-		tickers = append(tickers, "SPY", "VOO", "QQQ", "VTI", "IVV")
-	}
-
-	// Remove duplicates
-	seen := make(map[string]bool)
-	unique := make([]string, 0)
-	for _, ticker := range tickers {
-		if !seen[ticker] {
-			seen[ticker] = true
-			unique = append(unique, ticker)
-		}
-	}
-
-	return unique
-}
-
 // Helper: Get country from exchange code
 func (p *LiveProvider) getCountryFromExchange(exchange string) string {
-	exchangeMap := map[string]string{
-		"NYQ": "US", "NMS": "US", "PCX": "US", "NAS": "US", "NCM": "US", "NGM": "US",
-		"LSE": "GB", "LON": "GB",
-		"JSE": "ZA", "JNB": "ZA",
-		"FRA": "DE", "ETR": "DE", "XETR": "DE",
-		"TSE": "JP", "TYO": "JP",
-		"ASX": "AU",
-		"TSX": "CA",
-	}
-
-	exchangeUpper := strings.ToUpper(exchange)
-	if country, ok := exchangeMap[exchangeUpper]; ok {
+	if country := domain.ExchangeCountry(exchange); country != "" {
 		return country
 	}
 	return "UNKNOWN"
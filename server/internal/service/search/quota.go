@@ -0,0 +1,199 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExhausted is returned when an API's daily request budget is used up
+var ErrQuotaExhausted = errors.New("request quota exhausted")
+
+// ErrRateLimited is returned when no per-minute slot is free. It wraps
+// ErrQuotaExhausted: callers skip the request either way.
+var ErrRateLimited = fmt.Errorf("%w: per-minute limit reached", ErrQuotaExhausted)
+
+// QuotaTracker enforces a per-day and per-minute request budget shared by all
+// callers. Usage is persisted so restarts do not reset the daily count.
+type QuotaTracker struct {
+	mu          sync.Mutex
+	path        string
+	dailyLimit  int
+	minuteLimit int
+
+	day       string // UTC date the daily count applies to
+	dailyUsed int
+	recent    []time.Time // Request times within the last minute
+}
+
+// QuotaStatus reports remaining budget for the health endpoint
+type QuotaStatus struct {
+	DailyLimit      int    `json:"dailyLimit"`
+	DailyUsed       int    `json:"dailyUsed"`
+	DailyRemaining  int    `json:"dailyRemaining"`
+	MinuteLimit     int    `json:"minuteLimit"`
+	MinuteRemaining int    `json:"minuteRemaining"`
+	ResetsAt        string `json:"resetsAt"`
+}
+
+type quotaFile struct {
+	Day       string      `json:"day"`
+	DailyUsed int         `json:"dailyUsed"`
+	Recent    []time.Time `json:"recent"`
+}
+
+// NewQuotaTracker creates a tracker allowing dailyLimit requests per UTC day
+// and minuteLimit per rolling minute. Usage is persisted to path; an empty
+// path keeps it in memory only.
+func NewQuotaTracker(path string, dailyLimit, minuteLimit int) *QuotaTracker {
+	q := &QuotaTracker{
+		path:        path,
+		dailyLimit:  dailyLimit,
+		minuteLimit: minuteLimit,
+		day:         quotaDay(time.Now()),
+	}
+
+	if path != "" {
+		if err := q.load(); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to load quota state from %s: %v", path, err)
+		}
+	}
+
+	return q
+}
+
+// TryAcquire spends one request from the budget without waiting. It returns
+// ErrQuotaExhausted when the daily budget is used up and ErrRateLimited when
+// the per-minute limit is reached, so callers skip rather than block.
+func (q *QuotaTracker) TryAcquire() error {
+	wait, err := q.tryAcquire(time.Now())
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return fmt.Errorf("%w, next slot in %s", ErrRateLimited, wait.Round(time.Second))
+	}
+	return nil
+}
+
+// tryAcquire spends a request if possible, or returns how long to wait for one
+func (q *QuotaTracker) tryAcquire(now time.Time) (time.Duration, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(now)
+
+	if q.dailyLimit > 0 && q.dailyUsed >= q.dailyLimit {
+		return 0, fmt.Errorf("%w: %d of %d daily requests used", ErrQuotaExhausted, q.dailyUsed, q.dailyLimit)
+	}
+	if q.minuteLimit > 0 && len(q.recent) >= q.minuteLimit {
+		return q.recent[0].Add(time.Minute).Sub(now), nil
+	}
+
+	q.dailyUsed++
+	q.recent = append(q.recent, now)
+	q.save()
+	return 0, nil
+}
+
+// DailyRemaining returns how many requests are left today (-1 when unlimited)
+func (q *QuotaTracker) DailyRemaining() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(time.Now())
+	if q.dailyLimit <= 0 {
+		return -1
+	}
+	return q.dailyLimit - q.dailyUsed
+}
+
+// Status returns a snapshot of the remaining budget
+func (q *QuotaTracker) Status() QuotaStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.roll(now)
+
+	tomorrow := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	return QuotaStatus{
+		DailyLimit:      q.dailyLimit,
+		DailyUsed:       q.dailyUsed,
+		DailyRemaining:  max(q.dailyLimit-q.dailyUsed, 0),
+		MinuteLimit:     q.minuteLimit,
+		MinuteRemaining: max(q.minuteLimit-len(q.recent), 0),
+		ResetsAt:        tomorrow.Format(time.RFC3339),
+	}
+}
+
+// roll resets the daily count on a new UTC day and drops requests older than a minute
+func (q *QuotaTracker) roll(now time.Time) {
+	if day := quotaDay(now); day != q.day {
+		q.day = day
+		q.dailyUsed = 0
+	}
+
+	cutoff := now.Add(-time.Minute)
+	kept := q.recent[:0]
+	for _, at := range q.recent {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	q.recent = kept
+}
+
+func (q *QuotaTracker) load() error {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		return err
+	}
+
+	var state quotaFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("decode quota state: %w", err)
+	}
+
+	if state.Day == q.day {
+		q.dailyUsed = state.DailyUsed
+	}
+	q.recent = state.Recent
+	q.roll(time.Now())
+	return nil
+}
+
+// save persists usage; failures are logged since the in-memory count stays correct
+func (q *QuotaTracker) save() {
+	if q.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(quotaFile{Day: q.day, DailyUsed: q.dailyUsed, Recent: q.recent})
+	if err != nil {
+		log.Printf("Failed to encode quota state: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		log.Printf("Failed to persist quota state: %v", err)
+		return
+	}
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		log.Printf("Failed to persist quota state: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		log.Printf("Failed to persist quota state: %v", err)
+	}
+}
+
+func quotaDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
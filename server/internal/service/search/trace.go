@@ -0,0 +1,95 @@
+package search
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Trace collects how a search was served (cache hits, etc.) without changing
+// the Provider interface. Callers attach one with WithTrace; providers record
+// into it via TraceFrom, which returns nil - and nil-safe methods - when absent.
+type Trace struct {
+	mu       sync.Mutex
+	cacheHit bool
+	stale    bool
+	dataAsOf time.Time
+	sources  []SourceStatus
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context carrying a fresh Trace
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+// TraceFrom returns the Trace attached to ctx, or nil
+func TraceFrom(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// MarkCacheHit records that results were served from cache
+func (t *Trace) MarkCacheHit() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cacheHit = true
+}
+
+// CacheHit reports whether results were served from cache
+func (t *Trace) CacheHit() bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cacheHit
+}
+
+// MarkStale records that results past their TTL were served, and when they were fetched
+func (t *Trace) MarkStale(fetchedAt time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stale = true
+	if t.dataAsOf.IsZero() || fetchedAt.Before(t.dataAsOf) {
+		t.dataAsOf = fetchedAt
+	}
+}
+
+// Stale reports whether stale results were served, and the oldest fetch time
+func (t *Trace) Stale() (bool, time.Time) {
+	if t == nil {
+		return false, time.Time{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stale, t.dataAsOf
+}
+
+// RecordSource records what one data source did for the search
+func (t *Trace) RecordSource(status SourceStatus) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sources = append(t.sources, status)
+}
+
+// Sources returns the data sources recorded for the search, in order
+func (t *Trace) Sources() []SourceStatus {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]SourceStatus(nil), t.sources...)
+}
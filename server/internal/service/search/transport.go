@@ -0,0 +1,185 @@
+package search
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FixtureMode selects how outbound provider HTTP traffic is handled
+type FixtureMode string
+
+const (
+	FixtureModeLive   FixtureMode = "live"   // Call upstream APIs directly
+	FixtureModeRecord FixtureMode = "record" // Call upstream and write every response to disk
+	FixtureModeReplay FixtureMode = "replay" // Serve responses from disk, never touch the network
+)
+
+// redactedParams are stripped from URLs before they are hashed or written to disk
+var redactedParams = []string{"apikey", "api_key", "token"}
+
+// NewHTTPClient builds the client shared by the live providers. In record and
+// replay modes the transport is wrapped so responses are captured to, or
+// served from, fixtureDir.
+func NewHTTPClient(mode FixtureMode, fixtureDir string) *http.Client {
+	var transport http.RoundTripper = http.DefaultTransport
+
+	switch mode {
+	case FixtureModeRecord, FixtureModeReplay:
+		transport = &FixtureTransport{
+			mode: mode,
+			dir:  fixtureDir,
+			next: http.DefaultTransport,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}
+}
+
+// FixtureTransport is an http.RoundTripper that records responses to disk or
+// replays them, keyed by request method and (redacted) URL
+type FixtureTransport struct {
+	mode FixtureMode
+	dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+}
+
+// fixture is the on-disk representation of one recorded exchange
+type fixture struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+	RecordedAt time.Time   `json:"recordedAt"`
+}
+
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == FixtureModeReplay {
+		return t.replay(req)
+	}
+	return t.record(req)
+}
+
+func (t *FixtureTransport) replay(req *http.Request) (*http.Response, error) {
+	path := t.fixturePath(req)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture recorded for %s %s: %w", req.Method, redactURL(req.URL), err)
+	}
+
+	var fx fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fx.StatusCode, http.StatusText(fx.StatusCode)),
+		StatusCode:    fx.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fx.Header,
+		Body:          io.NopCloser(strings.NewReader(fx.Body)),
+		ContentLength: int64(len(fx.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *FixtureTransport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fx := fixture{
+		Method:     req.Method,
+		URL:        redactURL(req.URL),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       string(body),
+		RecordedAt: time.Now().UTC(),
+	}
+	fx.Header.Del("Set-Cookie")
+
+	if err := t.write(t.fixturePath(req), fx); err != nil {
+		// Recording is best effort - never fail the live request because of it
+		log.Printf("Failed to record fixture for %s: %v", fx.URL, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *FixtureTransport) write(path string, fx fixture) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fx); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// fixturePath returns <dir>/<host>/<hash>.json for the request
+func (t *FixtureTransport) fixturePath(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + redactURL(req.URL)))
+	return filepath.Join(t.dir, req.URL.Hostname(), hex.EncodeToString(sum[:8])+".json")
+}
+
+// redactURL removes credentials and sorts query parameters so that
+// recordings are stable and safe to commit
+func redactURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for _, param := range redactedParams {
+		query.Del(param)
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+	clean.RawQuery = strings.Join(parts, "&")
+
+	return clean.String()
+}
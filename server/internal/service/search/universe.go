@@ -0,0 +1,295 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Universe is the registry of tickers the live providers know how to fetch,
+// loaded from a versioned JSON file so new funds are a data change
+type Universe struct {
+	mu       sync.RWMutex
+	path     string
+	version  string
+	entries  []UniverseEntry
+	loadedAt time.Time
+	modTime  time.Time
+}
+
+// UniverseEntry describes one fetchable ticker. Region, sector and asset class
+// tags are matched case-insensitively against search criteria; list aliases
+// (e.g. "usa", "us", "united states") explicitly.
+type UniverseEntry struct {
+	Ticker     string   `json:"ticker"`
+	Name       string   `json:"name,omitempty"`
+	Exchange   string   `json:"exchange"`
+	Provider   string   `json:"provider,omitempty"`
+	Symbol     string   `json:"symbol,omitempty"` // Upstream symbol when it differs, e.g. STX40.JO
+	AssetClass string   `json:"assetClass"`
+	Regions    []string `json:"regions,omitempty"`
+	Sectors    []string `json:"sectors,omitempty"`
+	Sources    []string `json:"sources,omitempty"`  // Providers that can fetch it: "yahoo", "alpha_vantage"
+	Priority   int      `json:"priority,omitempty"` // 1 is most important; 0 means unranked
+	Default    bool     `json:"default,omitempty"`  // Fetched when criteria match nothing more specific
+}
+
+// UniverseQuery selects tickers from the registry
+type UniverseQuery struct {
+	Source           string // Only entries fetchable by this provider
+	Exchanges        []string
+	ExcludeExchanges []string
+	Markets          []string
+	Sectors          []string
+	AssetClasses     []string
+	Tickers          []string // Only these tickers or fetch symbols; markets and sectors are then ignored
+	Fallback         bool     // Return Default entries when nothing more specific matches
+}
+
+// UniverseInfo reports the loaded registry for the health endpoint
+type UniverseInfo struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Tickers  int    `json:"tickers"`
+	LoadedAt string `json:"loadedAt"`
+}
+
+type universeFile struct {
+	Version string          `json:"version"`
+	Tickers []UniverseEntry `json:"tickers"`
+}
+
+// LoadUniverse reads the registry at path
+func LoadUniverse(path string) (*Universe, error) {
+	u := &Universe{path: path}
+	if err := u.Reload(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// NewUniverse creates an in-memory registry, mainly for tests and tools
+func NewUniverse(version string, entries ...UniverseEntry) *Universe {
+	return &Universe{version: version, entries: entries, loadedAt: time.Now()}
+}
+
+// Reload re-reads the registry file. On error the current entries are kept.
+func (u *Universe) Reload() error {
+	if u.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(u.path)
+	if err != nil {
+		return fmt.Errorf("stat universe: %w", err)
+	}
+	data, err := os.ReadFile(u.path)
+	if err != nil {
+		return fmt.Errorf("read universe: %w", err)
+	}
+
+	var file universeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("decode universe %s: %w", u.path, err)
+	}
+	if file.Version == "" {
+		return fmt.Errorf("universe %s has no version", u.path)
+	}
+
+	seen := make(map[string]bool, len(file.Tickers))
+	for i, entry := range file.Tickers {
+		key := strings.ToUpper(entry.Exchange) + ":" + strings.ToUpper(entry.Ticker)
+		if entry.Ticker == "" || entry.Exchange == "" {
+			return fmt.Errorf("universe entry %d: ticker and exchange are required", i+1)
+		}
+		if seen[key] {
+			return fmt.Errorf("universe entry %d: duplicate ticker %s", i+1, key)
+		}
+		seen[key] = true
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version = file.Version
+	u.entries = file.Tickers
+	u.loadedAt = time.Now()
+	u.modTime = info.ModTime()
+	return nil
+}
+
+// Watch reloads the registry whenever the file changes, checking every interval
+func (u *Universe) Watch(ctx context.Context, interval time.Duration) {
+	if u.path == "" || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				info, err := os.Stat(u.path)
+				if err != nil {
+					continue
+				}
+
+				u.mu.RLock()
+				changed := !info.ModTime().Equal(u.modTime)
+				u.mu.RUnlock()
+
+				if changed {
+					if err := u.Reload(); err != nil {
+						log.Printf("Universe reload failed, keeping version %s: %v", u.Info().Version, err)
+						continue
+					}
+					log.Printf("Universe reloaded: version %s", u.Info().Version)
+				}
+			}
+		}
+	}()
+}
+
+// Info describes the loaded registry
+func (u *Universe) Info() UniverseInfo {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return UniverseInfo{
+		Path:     u.path,
+		Version:  u.version,
+		Tickers:  len(u.entries),
+		LoadedAt: u.loadedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// Tickers returns entries for the query, highest priority first. Entries of
+// a requested asset class that match a requested market or sector are
+// returned; failing that, with Fallback set, the Default entries.
+func (u *Universe) Tickers(query UniverseQuery) []UniverseEntry {
+	if u == nil {
+		return nil
+	}
+
+	u.mu.RLock()
+	candidates := make([]UniverseEntry, 0, len(u.entries))
+	for _, entry := range u.entries {
+		if query.Source != "" && len(entry.Sources) > 0 && !hasTag(entry.Sources, query.Source) {
+			continue
+		}
+		if len(query.Exchanges) > 0 && !hasTag(query.Exchanges, entry.Exchange) {
+			continue
+		}
+		if hasTag(query.ExcludeExchanges, entry.Exchange) {
+			continue
+		}
+		if len(query.AssetClasses) > 0 && !matchesAssetClass(entry.AssetClass, query.AssetClasses) {
+			continue
+		}
+		if len(query.Tickers) > 0 && !hasTag(query.Tickers, entry.Ticker) && !hasTag(query.Tickers, entry.FetchSymbol()) {
+			continue
+		}
+		candidates = append(candidates, entry)
+	}
+	u.mu.RUnlock()
+
+	if len(query.Tickers) > 0 {
+		return candidates
+	}
+
+	matched := filterEntries(candidates, func(entry UniverseEntry) bool {
+		return hasAny(entry.Regions, query.Markets) || hasAny(entry.Sectors, query.Sectors)
+	})
+	if len(matched) == 0 && query.Fallback {
+		matched = filterEntries(candidates, func(entry UniverseEntry) bool {
+			return entry.Default
+		})
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return rankOf(matched[i]) < rankOf(matched[j])
+	})
+	return matched
+}
+
+// Priority returns the ranking of ticker (lower is more important), or 0 if unranked
+func (u *Universe) Priority(ticker string) int {
+	if u == nil {
+		return 0
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, entry := range u.entries {
+		if strings.EqualFold(entry.Ticker, ticker) {
+			return entry.Priority
+		}
+	}
+	return 0
+}
+
+// FetchSymbol is the symbol to request upstream
+func (e UniverseEntry) FetchSymbol() string {
+	if e.Symbol != "" {
+		return e.Symbol
+	}
+	return e.Ticker
+}
+
+func filterEntries(entries []UniverseEntry, keep func(UniverseEntry) bool) []UniverseEntry {
+	result := make([]UniverseEntry, 0)
+	for _, entry := range entries {
+		if keep(entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// rankOf orders unranked entries after every ranked one
+func rankOf(entry UniverseEntry) int {
+	if entry.Priority <= 0 {
+		return int(^uint(0) >> 1)
+	}
+	return entry.Priority
+}
+
+func hasTag(tags []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, tag := range tags {
+		if strings.EqualFold(strings.TrimSpace(tag), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAny(tags, values []string) bool {
+	for _, value := range values {
+		if hasTag(tags, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAssetClass allows partial names, e.g. "equity" matches "equities"
+func matchesAssetClass(assetClass string, requested []string) bool {
+	assetClass = strings.ToLower(assetClass)
+	for _, value := range requested {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" && (strings.Contains(assetClass, value) || strings.Contains(value, assetClass)) {
+			return true
+		}
+	}
+	return false
+}
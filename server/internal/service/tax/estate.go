@@ -0,0 +1,133 @@
+package tax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// situsRule is one jurisdiction's estate tax on non-residents' holdings of
+// funds domiciled there
+type situsRule struct {
+	ExemptionUSD float64
+	TopRate      float64
+	// Investor countries whose estate tax treaty allows a pro-rata share of
+	// the resident exemption
+	TreatyRelief map[string]bool
+}
+
+// situsRules are keyed by fund domicile.
+//
+// US treaty countries are those listed by the IRS under "Estate & Gift Tax
+// Treaties (International)",
+// https://www.irs.gov/businesses/small-businesses-self-employed/estate-gift-tax-treaties-international,
+// including the 1947 estate tax convention with South Africa, plus Canada,
+// whose relief is in Article XXIX B of the US-Canada income tax treaty.
+var situsRules = map[string]situsRule{
+	"US": {
+		ExemptionUSD: 60000,
+		TopRate:      40,
+		TreatyRelief: map[string]bool{
+			"AU": true, "AT": true, "CA": true, "CH": true, "DE": true, "DK": true, "FI": true, "FR": true,
+			"GB": true, "GR": true, "IE": true, "IT": true, "JP": true, "NL": true, "NO": true, "ZA": true,
+		},
+	},
+}
+
+// SitusExposure reports whether holding etf exposes a resident of country to
+// a foreign estate tax. Funds without a reported domicile are assumed
+// domiciled where they are listed.
+func SitusExposure(etf domain.ETF, country string) (domain.SitusExposure, bool) {
+	country = strings.ToUpper(country)
+	domicile, assumed := fundDomicile(etf)
+
+	rule, ok := situsRules[domicile]
+	if !ok || domicile == country {
+		return domain.SitusExposure{}, false
+	}
+
+	exposure := domain.SitusExposure{
+		Jurisdiction: domicile,
+		FundDomicile: domicile,
+		ExemptionUSD: rule.ExemptionUSD,
+		TopRate:      rule.TopRate,
+		TreatyRelief: rule.TreatyRelief[country],
+	}
+
+	subject := fmt.Sprintf("%s is %s-domiciled", etf.Ticker, domicile)
+	if assumed {
+		subject = fmt.Sprintf("%s is listed in %s and presumed %s-domiciled", etf.Ticker, domicile, domicile)
+	}
+	exposure.Reason = fmt.Sprintf("%s: %s estate tax of up to %.0f%% can apply to %s residents' holdings above $%s at death",
+		subject, domicile, rule.TopRate, country, formatThousands(rule.ExemptionUSD))
+	if exposure.TreatyRelief {
+		exposure.Reason += fmt.Sprintf(" (the %s-%s estate tax treaty may allow a larger exemption)", domicile, country)
+	}
+	return exposure, true
+}
+
+// SitusAlternatives picks up to limit candidates tracking the same index as
+// etf without its estate tax exposure for a resident of country. Funds listed
+// in the investor's country come first, then the cheapest.
+func SitusAlternatives(etf domain.ETF, candidates []domain.ETF, country string, limit int) []domain.ETF {
+	index := normalizeIndex(etf.TrackingIndex)
+	if index == "" {
+		return nil
+	}
+
+	country = strings.ToUpper(country)
+	seen := map[string]bool{strings.ToUpper(etf.Ticker): true}
+	alternatives := make([]domain.ETF, 0)
+	for _, candidate := range candidates {
+		ticker := strings.ToUpper(candidate.Ticker)
+		if seen[ticker] || normalizeIndex(candidate.TrackingIndex) != index {
+			continue
+		}
+		if _, exposed := SitusExposure(candidate, country); exposed || strings.TrimSpace(candidate.Domicile) == "" {
+			continue
+		}
+		seen[ticker] = true
+		alternatives = append(alternatives, candidate)
+	}
+
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		localI := strings.EqualFold(alternatives[i].ExchangeCountry, country)
+		localJ := strings.EqualFold(alternatives[j].ExchangeCountry, country)
+		if localI != localJ {
+			return localI
+		}
+		return alternatives[i].TER < alternatives[j].TER
+	})
+
+	if len(alternatives) > limit {
+		alternatives = alternatives[:limit]
+	}
+	return alternatives
+}
+
+// fundDomicile returns the reported domicile, or the listing country for
+// funds that do not report one
+func fundDomicile(etf domain.ETF) (domicile string, assumed bool) {
+	if domicile := strings.ToUpper(strings.TrimSpace(etf.Domicile)); domicile != "" {
+		return domicile, false
+	}
+	return strings.ToUpper(strings.TrimSpace(etf.ExchangeCountry)), true
+}
+
+// normalizeIndex reduces index names to comparable form, e.g. "S&P 500 Index"
+// and "S&P 500" are the same index
+func normalizeIndex(index string) string {
+	index = strings.ToLower(strings.TrimSpace(index))
+	index = strings.TrimSuffix(index, " index")
+	return strings.Join(strings.Fields(index), " ")
+}
+
+func formatThousands(value float64) string {
+	digits := fmt.Sprintf("%.0f", value)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
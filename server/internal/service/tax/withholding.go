@@ -0,0 +1,322 @@
+// Package tax estimates the dividend tax drag of holding an ETF, from the
+// withholding applied where dividends originate, where the fund is domiciled
+// and where the investor lives.
+package tax
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"upstonk/internal/domain"
+)
+
+// TableVersion identifies the withholding and treaty rates below
+const TableVersion = "withholding_v1.0_2025"
+
+// statutoryRates are the dividend withholding rates a country applies to
+// non-residents without treaty relief (%)
+var statutoryRates = map[string]float64{
+	"US": 30,
+	"ZA": 20,
+	"GB": 0,
+	"IE": 25,
+	"LU": 15,
+	"DE": 26.375,
+	"FR": 25,
+	"NL": 15,
+	"CH": 35,
+	"JP": 20.42,
+	"CA": 25,
+	"AU": 30,
+}
+
+// treatyRates are reduced portfolio dividend rates, source -> recipient (%).
+// Luxembourg funds are left out of the US column: they cannot normally claim
+// the US treaty rate and suffer the full 30%.
+var treatyRates = map[string]map[string]float64{
+	"US": {"IE": 15, "ZA": 15, "GB": 15, "DE": 15, "NL": 15, "FR": 15, "CH": 15, "JP": 10, "CA": 15, "AU": 15},
+	"ZA": {"US": 15, "GB": 15, "IE": 10, "LU": 15, "DE": 15, "NL": 10},
+	"DE": {"US": 15, "ZA": 15, "GB": 15, "IE": 15, "LU": 15},
+	"FR": {"US": 15, "ZA": 15, "GB": 15, "IE": 15, "LU": 15},
+	"CH": {"US": 15, "ZA": 15, "GB": 15, "IE": 15, "LU": 15},
+	"JP": {"US": 10, "ZA": 15, "GB": 10, "IE": 15, "LU": 15},
+	"CA": {"US": 15, "ZA": 15, "GB": 15, "IE": 15, "LU": 15},
+	"NL": {"US": 15, "ZA": 10, "GB": 10, "IE": 15, "LU": 15},
+	"AU": {"US": 15, "ZA": 15, "GB": 15, "IE": 15},
+}
+
+// defaultForeignRate applies to dividends whose country of origin is unknown,
+// a typical developed-market treaty rate
+const defaultForeignRate = 15
+
+// distributionExempt lists fund domiciles that do not withhold on
+// distributions to non-resident investors
+var distributionExempt = map[string]bool{"IE": true, "LU": true, "GB": true}
+
+// localDividendRates are typical rates investors pay at home on dividends
+// received in a taxable account (%). Foreign withholding on distributions is
+// credited against them.
+var localDividendRates = map[string]float64{
+	"ZA": 20,
+	"GB": 8.75,
+	"US": 15,
+}
+
+// exemptAccounts are account types whose income is not taxed at home
+var exemptAccounts = map[string]map[string]bool{
+	"ZA": {"tfsa": true, "retirement_annuity": true, "preservation_fund": true},
+	"US": {"ira": true, "roth_ira": true, "401k": true},
+	"GB": {"isa": true},
+}
+
+// typicalYields stand in for an unreported dividend yield (%)
+var typicalYields = map[string]float64{
+	"equity":    2.0,
+	"property":  5.0,
+	"bond":      3.5,
+	"commodity": 0,
+}
+
+// regionCountries maps region labels to the country whose dividends dominate them
+var regionCountries = map[string]string{
+	"us":             "US",
+	"usa":            "US",
+	"united states":  "US",
+	"north america":  "US",
+	"za":             "ZA",
+	"south africa":   "ZA",
+	"south_africa":   "ZA",
+	"uk":             "GB",
+	"united kingdom": "GB",
+	"japan":          "JP",
+	"germany":        "DE",
+	"france":         "FR",
+	"switzerland":    "CH",
+	"canada":         "CA",
+	"australia":      "AU",
+}
+
+// Estimator computes the dividend tax drag of an ETF for an investor
+type Estimator struct{}
+
+func NewEstimator() *Estimator {
+	return &Estimator{}
+}
+
+// Estimate returns the expected annual tax drag of etf for a resident of
+// country holding it in accountType
+func (e *Estimator) Estimate(etf domain.ETF, country, accountType string) domain.TaxDrag {
+	country = strings.ToUpper(country)
+	drag := domain.TaxDrag{
+		FundDomicile: strings.ToUpper(strings.TrimSpace(etf.Domicile)),
+		Confidence:   domain.ConfidenceHigh,
+		Notes:        []string{},
+		TableVersion: TableVersion,
+	}
+	lower := func(level domain.ConfidenceLevel) {
+		if domain.ConfidenceRank[level] < domain.ConfidenceRank[drag.Confidence] {
+			drag.Confidence = level
+		}
+	}
+
+	if drag.FundDomicile == "" {
+		drag.FundDomicile = strings.ToUpper(etf.ExchangeCountry)
+		drag.Notes = append(drag.Notes, fmt.Sprintf("Domicile not reported - assumed %s from the listing", drag.FundDomicile))
+		lower(domain.ConfidenceLow)
+	}
+
+	class := assetClass(etf)
+	drag.DividendYield = etf.DividendYield
+	if drag.DividendYield <= 0 {
+		drag.DividendYield = typicalYields[class]
+		drag.YieldAssumed = true
+		drag.Notes = append(drag.Notes, fmt.Sprintf("Dividend yield not reported - assumed %.1f%% for %s funds", drag.DividendYield, class))
+		lower(domain.ConfidenceMedium)
+	}
+
+	drag.FundLevelRate = e.fundLevelRate(etf, class, drag.FundDomicile, &drag, lower)
+
+	exempt := exemptAccounts[country][strings.ToLower(accountType)]
+	drag.InvestorLevelRate = e.investorLevelRate(etf, class, drag.FundDomicile, country, exempt, &drag)
+
+	drag.EffectiveRate = round(100 - (100-drag.FundLevelRate)*(100-drag.InvestorLevelRate)/100)
+	drag.AnnualDrag = round(drag.DividendYield * drag.EffectiveRate / 100)
+	return drag
+}
+
+// fundLevelRate is the share of dividends withheld by the countries the
+// underlying companies are listed in before the income reaches the fund
+func (e *Estimator) fundLevelRate(etf domain.ETF, class, domicile string, drag *domain.TaxDrag, lower func(domain.ConfidenceLevel)) float64 {
+	switch {
+	case class == "bond" || class == "commodity":
+		drag.Notes = append(drag.Notes, fmt.Sprintf("%s income is generally paid without withholding", titleCase(class)))
+		return 0
+	case etf.IsSynthetic:
+		drag.Notes = append(drag.Notes, "Synthetic replication - index dividends arrive through the swap without withholding")
+		return 0
+	}
+
+	sources, derived := sourceCountries(etf)
+	switch derived {
+	case "regions":
+		lower(domain.ConfidenceMedium)
+	case "":
+		sources = map[string]float64{domicile: 1}
+		drag.Notes = append(drag.Notes, "Geographic exposure not reported - assumed domestic holdings")
+		lower(domain.ConfidenceLow)
+	}
+
+	rate := 0.0
+	for _, source := range sortedKeys(sources) {
+		weight := sources[source]
+		if source == domicile {
+			continue
+		}
+		withheld, known := withholdingRate(source, domicile)
+		if !known {
+			lower(domain.ConfidenceMedium)
+		}
+		rate += weight * withheld
+		if weight >= 0.2 && source != "" {
+			drag.Notes = append(drag.Notes, fmt.Sprintf("%s dividends withheld at %.0f%% before reaching the %s-domiciled fund", source, withheld, domicile))
+		}
+	}
+	return round(rate)
+}
+
+// investorLevelRate is withholding on the fund's distributions plus any home
+// tax on them, with the withholding credited against the home tax
+func (e *Estimator) investorLevelRate(etf domain.ETF, class, domicile, country string, exempt bool, drag *domain.TaxDrag) float64 {
+	if strings.Contains(strings.ToLower(etf.DividendTreatment), "accum") {
+		drag.Notes = append(drag.Notes, "Accumulating fund - no distributions to withhold on")
+		return 0
+	}
+
+	withheld := 0.0
+	if domicile != country && !distributionExempt[domicile] {
+		withheld, _ = withholdingRate(domicile, country)
+		drag.Notes = append(drag.Notes, fmt.Sprintf("Distributions from %s-domiciled funds withheld at %.0f%% for %s residents", domicile, withheld, country))
+	}
+
+	if exempt {
+		if withheld > 0 {
+			drag.Notes = append(drag.Notes, "Tax-free account - foreign withholding cannot be reclaimed")
+		}
+		return withheld
+	}
+
+	if class == "bond" || class == "commodity" {
+		drag.Notes = append(drag.Notes, "Interest distributions are taxed at your marginal rate at home - not included")
+		return withheld
+	}
+	local, taxed := localDividendRates[country]
+	if taxed && local > withheld {
+		drag.Notes = append(drag.Notes, fmt.Sprintf("Distributions taxed at about %g%% at home in a taxable account", local))
+		return local
+	}
+	return withheld
+}
+
+// withholdingRate is the treaty rate from source to recipient, falling back
+// to the source's statutory rate. known is false for unlisted sources.
+func withholdingRate(source, recipient string) (rate float64, known bool) {
+	if rate, ok := treatyRates[source][recipient]; ok {
+		return rate, true
+	}
+	if rate, ok := statutoryRates[source]; ok {
+		return rate, true
+	}
+	return defaultForeignRate, false
+}
+
+// sourceCountries splits the fund's holdings by country as weights summing
+// to 1, from country exposure or, failing that, region labels. Holdings in
+// unrecognised regions are keyed "". derived names the data used, and is
+// empty when the fund reports no geography.
+func sourceCountries(etf domain.ETF) (weights map[string]float64, derived string) {
+	if countries := etf.GeographicExposure.Countries; len(countries) > 0 {
+		weights = make(map[string]float64, len(countries))
+		for country, weight := range countries {
+			weights[strings.ToUpper(country)] += weight
+		}
+		return normalizeWeights(weights), "countries"
+	}
+
+	regions := etf.GeographicExposure.Regions
+	if len(regions) == 0 {
+		return nil, ""
+	}
+
+	weights = make(map[string]float64)
+	total := 0.0
+	for _, weight := range regions {
+		total += weight
+	}
+	if total > 0 {
+		for region, weight := range regions {
+			weights[regionCountries[strings.ToLower(strings.TrimSpace(region))]] += weight
+		}
+		return normalizeWeights(weights), "regions"
+	}
+
+	// Unweighted region labels: split evenly across the countries they name
+	for region := range regions {
+		if country, ok := regionCountries[strings.ToLower(strings.TrimSpace(region))]; ok {
+			weights[country] = 1
+		}
+	}
+	if len(weights) == 0 {
+		weights[""] = 1
+	}
+	return normalizeWeights(weights), "regions"
+}
+
+// assetClass reduces the ETF to the income type that decides withholding
+func assetClass(etf domain.ETF) string {
+	class := strings.ToLower(etf.AssetClass)
+	switch {
+	case strings.Contains(class, "bond") || strings.Contains(class, "fixed income"):
+		return "bond"
+	case strings.Contains(class, "property") || strings.Contains(class, "real estate") || strings.Contains(class, "reit"):
+		return "property"
+	case strings.Contains(class, "commodit") || strings.Contains(class, "gold"):
+		return "commodity"
+	}
+	return "equity"
+}
+
+func normalizeWeights(weights map[string]float64) map[string]float64 {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	if total <= 0 {
+		return weights
+	}
+	for key := range weights {
+		weights[key] /= total
+	}
+	return weights
+}
+
+func sortedKeys(weights map[string]float64) []string {
+	keys := make([]string, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
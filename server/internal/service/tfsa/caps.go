@@ -0,0 +1,137 @@
+// Package tfsa plans contributions to South African tax-free savings
+// accounts within the annual and lifetime caps of Section 12T.
+package tfsa
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CapsVersion is one set of contribution caps, in force from the tax year
+// starting on EffectiveFrom until the next version
+type CapsVersion struct {
+	Version       string  `json:"version"`
+	EffectiveFrom string  `json:"effectiveFrom"` // First day of the first tax year it applies to (1 March)
+	AnnualCap     float64 `json:"annualCap"`     // Rand per tax year, across all of an investor's TFSAs
+	LifetimeCap   float64 `json:"lifetimeCap"`
+	PenaltyRate   float64 `json:"penaltyRate"` // % tax on contributions above either cap
+	Reference     string  `json:"reference,omitempty"`
+
+	from time.Time
+}
+
+// Caps is the history of contribution caps
+type Caps struct {
+	versions []CapsVersion // Oldest first
+	path     string
+}
+
+// DefaultCaps returns the caps legislated since TFSAs were introduced
+func DefaultCaps() *Caps {
+	version := func(name string, year int, annual float64) CapsVersion {
+		from := time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		return CapsVersion{
+			Version:       name,
+			EffectiveFrom: from.Format("2006-01-02"),
+			AnnualCap:     annual,
+			LifetimeCap:   500000,
+			PenaltyRate:   40,
+			from:          from,
+		}
+	}
+	return &Caps{versions: []CapsVersion{
+		version("tfsa_za_caps_v1.0_2015", 2015, 30000),
+		version("tfsa_za_caps_v1.1_2017", 2017, 33000),
+		version("tfsa_za_caps_v1.2_2020", 2020, 36000),
+	}}
+}
+
+// LoadCaps reads a caps file: {"versions": [...]}
+func LoadCaps(path string) (*Caps, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read caps file: %w", err)
+	}
+
+	caps, err := ParseCaps(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	caps.path = path
+	return caps, nil
+}
+
+// ParseCaps decodes and validates a caps file
+func ParseCaps(data []byte) (*Caps, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+
+	var file struct {
+		Versions []CapsVersion `json:"versions"`
+	}
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode caps: %w", err)
+	}
+	return newCaps(file.Versions)
+}
+
+func newCaps(versions []CapsVersion) (*Caps, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("at least one caps version is required")
+	}
+
+	for i := range versions {
+		v := &versions[i]
+		from, err := time.Parse("2006-01-02", v.EffectiveFrom)
+		switch {
+		case v.Version == "":
+			return nil, fmt.Errorf("version is required")
+		case err != nil:
+			return nil, fmt.Errorf("%s: effectiveFrom must be YYYY-MM-DD: %w", v.Version, err)
+		case v.AnnualCap <= 0 || v.LifetimeCap < v.AnnualCap:
+			return nil, fmt.Errorf("%s: annualCap must be positive and no more than lifetimeCap", v.Version)
+		case v.PenaltyRate < 0 || v.PenaltyRate > 100:
+			return nil, fmt.Errorf("%s: penaltyRate must be between 0 and 100", v.Version)
+		}
+		v.from = from
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].from.Before(versions[j].from) })
+	return &Caps{versions: versions}, nil
+}
+
+// For returns the caps in force for a tax year. Tax years are named by the
+// year they end: 2026 runs from 1 March 2025 to 28 February 2026. Years before
+// the first version use it; later years use the latest.
+func (c *Caps) For(taxYear int) CapsVersion {
+	start := time.Date(taxYear-1, time.March, 1, 0, 0, 0, 0, time.UTC)
+	selected := c.versions[0]
+	for _, v := range c.versions {
+		if !v.from.After(start) {
+			selected = v
+		}
+	}
+	return selected
+}
+
+// Versions lists the cap versions, oldest first
+func (c *Caps) Versions() []CapsVersion {
+	return append([]CapsVersion(nil), c.versions...)
+}
+
+// Path is the file the caps were loaded from, if any
+func (c *Caps) Path() string {
+	return c.path
+}
+
+// TaxYear returns the tax year containing date
+func TaxYear(date time.Time) int {
+	if date.Month() >= time.March {
+		return date.Year() + 1
+	}
+	return date.Year()
+}
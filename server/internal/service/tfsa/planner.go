@@ -0,0 +1,286 @@
+package tfsa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultExpectedReturn is the annual return (%, before fund costs) projected
+// when a request does not give one
+const DefaultExpectedReturn = 8.0
+
+// Contribution is money already paid into TFSAs in one tax year
+type Contribution struct {
+	TaxYear int
+	Amount  float64
+}
+
+// Holding is an ETF the contributions are split across
+type Holding struct {
+	Ticker string
+	Weight float64 // % of each contribution
+	TER    float64 // % per year, 0 when unknown
+}
+
+// PlanInput describes the investor's history and intended contributions
+type PlanInput struct {
+	Prior          []Contribution
+	CurrentValue   *float64 // Value of existing TFSAs; nil projects prior contributions from cost
+	Monthly        float64
+	Years          int
+	ExpectedReturn float64 // % per year before fund costs
+	Holdings       []Holding
+	Start          time.Time // First contribution month
+}
+
+// Plan is a contribution schedule within the caps and its projected value
+type Plan struct {
+	CapsVersion       string       `json:"capsVersion"`
+	AnnualCap         float64      `json:"annualCap"`
+	LifetimeCap       float64      `json:"lifetimeCap"`
+	PenaltyRate       float64      `json:"penaltyRate"`
+	CurrentTaxYear    int          `json:"currentTaxYear"`
+	ContributedToDate float64      `json:"contributedToDate"`
+	AnnualHeadroom    float64      `json:"annualHeadroom"`   // Left in the current tax year
+	LifetimeHeadroom  float64      `json:"lifetimeHeadroom"` // Left before the lifetime cap
+	OverContributions []Excess     `json:"overContributions,omitempty"`
+	Schedule          []YearPlan   `json:"schedule"`
+	LifetimeCapYear   int          `json:"lifetimeCapYear,omitempty"` // Tax year the lifetime cap is reached
+	Projection        Projection   `json:"projection"`
+	Allocations       []Allocation `json:"allocations"`
+	Warnings          []string     `json:"warnings,omitempty"`
+}
+
+// Excess is a past contribution above a cap and the tax it attracts
+type Excess struct {
+	TaxYear int     `json:"taxYear"`
+	Cap     string  `json:"cap"` // "annual" or "lifetime"
+	Amount  float64 `json:"amount"`
+	Penalty float64 `json:"penalty"`
+}
+
+// YearPlan is the scheduled contribution for one tax year
+type YearPlan struct {
+	TaxYear                 int     `json:"taxYear"`
+	CapsVersion             string  `json:"capsVersion"`
+	Months                  int     `json:"months"`       // Months of the tax year inside the plan
+	Contribution            float64 `json:"contribution"` // Scheduled this tax year
+	AnnualHeadroom          float64 `json:"annualHeadroom"`
+	Capped                  bool    `json:"capped"` // Some months were reduced to stay within a cap
+	CumulativeContributions float64 `json:"cumulativeContributions"`
+	ProjectedValue          float64 `json:"projectedValue"` // At the end of the tax year or plan
+}
+
+// Projection is the tax-free value at the end of the horizon
+type Projection struct {
+	Years              int     `json:"years"`
+	ExpectedReturn     float64 `json:"expectedReturn"` // % per year before costs
+	WeightedTER        float64 `json:"weightedTer"`
+	NetReturn          float64 `json:"netReturn"`
+	StartingValue      float64 `json:"startingValue"`
+	TotalContributions float64 `json:"totalContributions"` // Scheduled in the plan
+	ProjectedValue     float64 `json:"projectedValue"`
+	TaxFreeGrowth      float64 `json:"taxFreeGrowth"`
+}
+
+// Allocation is one holding's share of the scheduled contributions
+type Allocation struct {
+	Ticker             string  `json:"ticker"`
+	Weight             float64 `json:"weight"`
+	FirstMonthAmount   float64 `json:"firstMonthAmount"`
+	TotalContributions float64 `json:"totalContributions"`
+}
+
+// Planner schedules TFSA contributions against versioned caps
+type Planner struct {
+	caps *Caps
+}
+
+func NewPlanner(caps *Caps) *Planner {
+	return &Planner{caps: caps}
+}
+
+// Caps returns the cap history the planner applies
+func (p *Planner) Caps() *Caps {
+	return p.caps
+}
+
+// Plan schedules input.Monthly each month for input.Years, reducing months
+// that would breach the annual or lifetime cap in force for their tax year
+func (p *Planner) Plan(input PlanInput) Plan {
+	current := TaxYear(input.Start)
+	caps := p.caps.For(current)
+
+	plan := Plan{
+		CapsVersion:    caps.Version,
+		AnnualCap:      caps.AnnualCap,
+		LifetimeCap:    caps.LifetimeCap,
+		PenaltyRate:    caps.PenaltyRate,
+		CurrentTaxYear: current,
+		Schedule:       []YearPlan{},
+		Warnings:       []string{},
+	}
+
+	// Contributions already made, per tax year
+	paid := make(map[int]float64)
+	for _, contribution := range input.Prior {
+		paid[contribution.TaxYear] += contribution.Amount
+	}
+	plan.ContributedToDate, plan.OverContributions = p.excess(paid)
+	for _, excess := range plan.OverContributions {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"Tax year %d exceeded the %s cap by R%.2f - SARS levies %.0f%% (R%.2f) on the excess",
+			excess.TaxYear, excess.Cap, excess.Amount, p.caps.For(excess.TaxYear).PenaltyRate, excess.Penalty))
+	}
+
+	plan.AnnualHeadroom = math.Max(0, caps.AnnualCap-paid[current])
+	plan.LifetimeHeadroom = math.Max(0, caps.LifetimeCap-plan.ContributedToDate)
+	plan.AnnualHeadroom = math.Min(plan.AnnualHeadroom, plan.LifetimeHeadroom)
+
+	weightedTER, totalWeight := 0.0, 0.0
+	for _, holding := range input.Holdings {
+		weightedTER += holding.TER * holding.Weight
+		totalWeight += holding.Weight
+	}
+	if totalWeight > 0 {
+		weightedTER /= totalWeight
+	}
+	netReturn := input.ExpectedReturn - weightedTER
+	monthlyGrowth := math.Pow(1+netReturn/100, 1.0/12) - 1
+
+	value := plan.ContributedToDate
+	if input.CurrentValue != nil {
+		value = *input.CurrentValue
+	} else if value > 0 {
+		plan.Warnings = append(plan.Warnings, "Current value not given - existing contributions are projected from cost")
+	}
+	startingValue := value
+
+	lifetimeUsed := plan.ContributedToDate
+	scheduled := 0.0
+	var year *YearPlan
+	month := time.Date(input.Start.Year(), input.Start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < input.Years*12; i, month = i+1, month.AddDate(0, 1, 0) {
+		taxYear := TaxYear(month)
+		caps := p.caps.For(taxYear)
+		if year == nil || year.TaxYear != taxYear {
+			plan.Schedule = append(plan.Schedule, YearPlan{TaxYear: taxYear, CapsVersion: caps.Version})
+			year = &plan.Schedule[len(plan.Schedule)-1]
+		}
+
+		amount := math.Min(input.Monthly, math.Max(0, caps.AnnualCap-paid[taxYear]))
+		amount = math.Min(amount, math.Max(0, caps.LifetimeCap-lifetimeUsed))
+		if amount < input.Monthly {
+			year.Capped = true
+			if lifetimeUsed+amount >= caps.LifetimeCap && plan.LifetimeCapYear == 0 {
+				plan.LifetimeCapYear = taxYear
+			}
+		}
+
+		paid[taxYear] += amount
+		lifetimeUsed += amount
+		scheduled += amount
+		value = (value + amount) * (1 + monthlyGrowth)
+
+		year.Months++
+		year.Contribution += amount
+		year.AnnualHeadroom = math.Max(0, math.Min(caps.AnnualCap-paid[taxYear], caps.LifetimeCap-lifetimeUsed))
+		year.CumulativeContributions = lifetimeUsed
+		year.ProjectedValue = round2(value)
+	}
+
+	for i := range plan.Schedule {
+		plan.Schedule[i].Contribution = round2(plan.Schedule[i].Contribution)
+		plan.Schedule[i].AnnualHeadroom = round2(plan.Schedule[i].AnnualHeadroom)
+		plan.Schedule[i].CumulativeContributions = round2(plan.Schedule[i].CumulativeContributions)
+	}
+
+	if input.Monthly*12 > caps.AnnualCap {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"R%.2f a month is R%.2f a year, above the R%.0f annual cap - months beyond the cap are scheduled at the remaining headroom",
+			input.Monthly, input.Monthly*12, caps.AnnualCap))
+	}
+	if plan.LifetimeCapYear != 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"The R%.0f lifetime cap is reached in tax year %d - later contributions belong in another account",
+			caps.LifetimeCap, plan.LifetimeCapYear))
+	}
+
+	plan.Projection = Projection{
+		Years:              input.Years,
+		ExpectedReturn:     input.ExpectedReturn,
+		WeightedTER:        round2(weightedTER),
+		NetReturn:          round2(netReturn),
+		StartingValue:      round2(startingValue),
+		TotalContributions: round2(scheduled),
+		ProjectedValue:     round2(value),
+		TaxFreeGrowth:      round2(value - startingValue - scheduled),
+	}
+
+	plan.Allocations = make([]Allocation, 0, len(input.Holdings))
+	firstMonth := 0.0
+	if len(plan.Schedule) > 0 && plan.Schedule[0].Months > 0 {
+		firstMonth = math.Min(input.Monthly, plan.AnnualHeadroom)
+	}
+	for _, holding := range input.Holdings {
+		share := 0.0
+		if totalWeight > 0 {
+			share = holding.Weight / totalWeight
+		}
+		plan.Allocations = append(plan.Allocations, Allocation{
+			Ticker:             holding.Ticker,
+			Weight:             holding.Weight,
+			FirstMonthAmount:   round2(firstMonth * share),
+			TotalContributions: round2(scheduled * share),
+		})
+	}
+
+	return plan
+}
+
+// excess totals past contributions and finds those above the annual cap of
+// their year or the lifetime cap, in tax year order
+func (p *Planner) excess(paid map[int]float64) (float64, []Excess) {
+	years := make([]int, 0, len(paid))
+	for year := range paid {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	var excesses []Excess
+	total := 0.0
+	for _, year := range years {
+		caps := p.caps.For(year)
+		amount := paid[year]
+
+		annualExcess := math.Max(0, amount-caps.AnnualCap)
+		if annualExcess > 0 {
+			excesses = append(excesses, Excess{
+				TaxYear: year,
+				Cap:     "annual",
+				Amount:  round2(annualExcess),
+				Penalty: round2(annualExcess * caps.PenaltyRate / 100),
+			})
+		}
+
+		// The lifetime excess is only what the annual excess has not already counted
+		lifetimeExcess := math.Max(0, total+amount-caps.LifetimeCap) - math.Max(0, total-caps.LifetimeCap)
+		lifetimeExcess = math.Max(0, lifetimeExcess-annualExcess)
+		if lifetimeExcess > 0 {
+			excesses = append(excesses, Excess{
+				TaxYear: year,
+				Cap:     "lifetime",
+				Amount:  round2(lifetimeExcess),
+				Penalty: round2(lifetimeExcess * caps.PenaltyRate / 100),
+			})
+		}
+		total += amount
+	}
+	return total, excesses
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}